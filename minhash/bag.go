@@ -0,0 +1,154 @@
+package minhash
+
+import (
+	"strconv"
+	"strings"
+)
+
+// WordBag is a multiset of lower cased words (or shingles).
+// Unlike WordSet it counts how often every word occurs,
+// so repetition (keyword stuffing, ...) shows up in the
+// similarity.
+type WordBag struct {
+	id     DocID
+	counts map[string]int
+	size   int
+}
+
+func NewWordBag() *WordBag {
+	var bag WordBag
+	bag.counts = map[string]int{}
+
+	return &bag
+}
+
+func NewWordBagFromText(text string) *WordBag {
+	return NewWordBagFromTextWith(text, DefaultShingler)
+}
+
+// NewWordBagFromTextWith builds a WordBag holding
+// the shingles produced by the given Shingler
+func NewWordBagFromTextWith(text string, sh Shingler) *WordBag {
+	bag := NewWordBag()
+
+	for _, w := range sh.Shingles(text) {
+		bag.Add(w)
+	}
+
+	return bag
+}
+
+// ID of the document the bag was built from
+func (this *WordBag) ID() DocID {
+	return this.id
+}
+
+func (this *WordBag) SetID(id DocID) {
+	this.id = id
+}
+
+// Add adds one occurrence of the word
+func (this *WordBag) Add(word string) {
+	this.counts[strings.ToLower(word)] += 1
+	this.size += 1
+}
+
+// Remove removes one occurrence of the word
+func (this *WordBag) Remove(word string) {
+	word = strings.ToLower(word)
+
+	if n, ok := this.counts[word]; ok {
+		if n == 1 {
+			delete(this.counts, word)
+		} else {
+			this.counts[word] = n - 1
+		}
+		this.size -= 1
+	}
+}
+
+// Count is the number of occurrences of the word
+func (this *WordBag) Count(word string) int {
+	return this.counts[strings.ToLower(word)]
+}
+
+// Len is the number of distinct words
+func (this *WordBag) Len() int {
+	return len(this.counts)
+}
+
+// Size is the number of words counting repeats
+func (this *WordBag) Size() int {
+	return this.size
+}
+
+// WordSet returns the distinct words of the bag
+func (this *WordBag) WordSet() *WordSet {
+	ws := NewWordSet()
+	ws.id = this.id
+
+	for word := range this.counts {
+		ws.membership[word] = struct{}{}
+	}
+	return ws
+}
+
+// BagJaccard is the generalized (Ruzicka) Jaccard index of two
+// bags, the sum of the smaller counts of every word over the sum
+// of the larger counts. It's the Jaccard index when no word repeats.
+func BagJaccard(left, right *WordBag) float64 {
+	min := 0
+	for word, n := range left.counts {
+		if m := right.counts[word]; m < n {
+			min += m
+		} else {
+			min += n
+		}
+	}
+
+	// sum of max = |A| + |B| - sum of min
+	return ratio(float64(min), float64(left.Size()+right.Size()-min))
+}
+
+// MultisetShingler wraps a Shingler so that repeated shingles
+// are told apart by their occurrence: the second "a b c" becomes
+// "a b c" followed by a separator and 2, and so on. The Jaccard
+// index of the resulting sets is BagJaccard of the original bags,
+// so MinHash signatures built with it estimate bag similarity.
+// Documents without repeated shingles shingle as before.
+type MultisetShingler struct {
+	Shingler Shingler
+}
+
+// separates a shingle from its occurrence number
+const occurrenceSeparator = "\x1f"
+
+func NewMultisetShingler(sh Shingler) *MultisetShingler {
+	return &MultisetShingler{Shingler: sh}
+}
+
+func (this *MultisetShingler) Shingles(text string) []string {
+	shingles := this.Shingler.Shingles(text)
+	seen := map[string]int{}
+
+	for i, s := range shingles {
+		// counted like WordBag does, without regard to case
+		key := strings.ToLower(s)
+		seen[key] += 1
+
+		if n := seen[key]; n > 1 {
+			shingles[i] = s + occurrenceSeparator + strconv.Itoa(n)
+		}
+	}
+	return shingles
+}
+
+func (this *MultisetShingler) Hashes(text string) []uint32 {
+	return HashShingles(this.Shingles(text))
+}
+
+// GenerateMultisetMinHash generates a minhash from a document
+// string that estimates BagJaccard instead of the Jaccard index
+func GenerateMultisetMinHash(d string) MinHash {
+	return GenerateMinHashWith(d, NewMultisetShingler(DefaultShingler))
+}
@@ -0,0 +1,71 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWordBag(t *testing.T) {
+	bag := NewWordBag()
+	bag.Add("Node.js")
+	bag.Add("node.js")
+	bag.Add("MySQL")
+
+	assert.Equal(t, 2, bag.Count("NODE.JS"))
+	assert.Equal(t, 2, bag.Len())
+	assert.Equal(t, 3, bag.Size())
+
+	bag.Remove("node.js")
+	assert.Equal(t, 1, bag.Count("node.js"))
+	bag.Remove("node.js")
+	bag.Remove("node.js")
+	assert.Equal(t, 0, bag.Count("node.js"))
+	assert.Equal(t, 1, bag.Len())
+	assert.Equal(t, 1, bag.Size())
+
+	assert.True(t, bag.WordSet().Equal(newSet("mysql")))
+}
+
+func TestBagJaccard(t *testing.T) {
+	s := "hiring java developer java developer java developer"
+	stuffed := "hiring java developer java developer java developer java developer java developer"
+
+	// as sets the stuffing is invisible
+	assert.Equal(t, 1.0, JaccardSimilarity(s, stuffed))
+
+	// 1 + 2 + 2 shared occurrences out of 1 + 4 + 4
+	left := NewWordBagFromText(s)
+	right := NewWordBagFromText(stuffed)
+	assert.Equal(t, 5.0/9.0, BagJaccard(left, right))
+	assert.Equal(t, 1.0, BagJaccard(left, left))
+	assert.Equal(t, 0.0, BagJaccard(NewWordBag(), NewWordBag()))
+
+	// without repeats it is the Jaccard index
+	a := "Excellent job opportunity! need Node.js, MYSQL and resume"
+	b := "Excellent job opportunity! need ASP.NET, MySQL and resume"
+	assert.Equal(t, JaccardSimilarity(a, b), BagJaccard(NewWordBagFromText(a), NewWordBagFromText(b)))
+}
+
+func TestMultisetShingler(t *testing.T) {
+	sh := NewMultisetShingler(NewWordShingler(1))
+	assert.Equal(t, []string{"a", "b", "a\x1f2", "a\x1f3"}, sh.Shingles("a b a a"))
+
+	s := "hiring java developer java developer java developer"
+	stuffed := strings.Repeat("java developer ", 20) + "hiring java developer"
+
+	// the exact Jaccard index of the augmented sets is BagJaccard
+	msh := NewMultisetShingler(DefaultShingler)
+	assert.Equal(t,
+		BagJaccard(NewWordBagFromText(s), NewWordBagFromText(stuffed)),
+		JaccardDistance(NewWordSetFromTextWith(s, msh), NewWordSetFromTextWith(stuffed, msh)))
+
+	// and the signatures estimate it
+	estimate := minHashSimilarity(GenerateMultisetMinHash(s), GenerateMultisetMinHash(stuffed))
+	assert.True(t, math.Abs(estimate-BagJaccard(NewWordBagFromText(s), NewWordBagFromText(stuffed))) < 0.3)
+
+	// documents without repeats sign the same as before
+	a := "Excellent job opportunity! need Node.js, MYSQL and resume"
+	assert.Equal(t, GenerateMinHash(a), GenerateMultisetMinHash(a))
+}
@@ -0,0 +1,251 @@
+package minhash
+
+import (
+	"go/scanner"
+	"go/token"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Placeholders used for normalized identifiers and literals.
+// Renaming a variable or changing a constant doesn't
+// change the shingles of the code once normalized.
+const (
+	identPlaceholder   = "$ID"
+	literalPlaceholder = "$LIT"
+)
+
+// CodeLanguage picks the lexer used by CodeShingler
+type CodeLanguage int
+
+const (
+	// Go source, lexed with go/scanner
+	GoCode CodeLanguage = iota
+
+	// Any language with C like lexical structure
+	// (C, C++, Java, JavaScript, C#, ...)
+	CLikeCode
+)
+
+// CodeShingler builds shingles out of K consecutive
+// lexical tokens of source code. Comments and
+// whitespace are dropped so formatting changes
+// don't affect similarity.
+//
+// With NormalizeIdentifiers every identifier is replaced
+// by a placeholder, and with NormalizeLiterals every number,
+// string and character literal is, so clones with renamed
+// variables or changed constants still match.
+type CodeShingler struct {
+	K                    int
+	Language             CodeLanguage
+	NormalizeIdentifiers bool
+	NormalizeLiterals    bool
+}
+
+// NewCodeShingler returns a CodeShingler for clone detection
+// with identifier and literal normalization turned on.
+// It panics if k is less than 1.
+func NewCodeShingler(k int, lang CodeLanguage) *CodeShingler {
+	if k < 1 {
+		panic("minhash: CodeShingler needs K >= 1, got " + strconv.Itoa(k))
+	}
+	return &CodeShingler{
+		K:                    k,
+		Language:             lang,
+		NormalizeIdentifiers: true,
+		NormalizeLiterals:    true,
+	}
+}
+
+func (this *CodeShingler) Shingles(text string) []string {
+	if this.K < 1 {
+		return []string{}
+	}
+	return joinWindows(this.Tokens(text), this.K)
+}
+
+func (this *CodeShingler) Hashes(text string) []uint32 {
+	return HashShingles(this.Shingles(text))
+}
+
+// Tokens lexes the source code, applying the
+// configured normalization
+func (this *CodeShingler) Tokens(src string) []string {
+	if this.Language == GoCode {
+		return this.goTokens(src)
+	}
+	return this.clikeTokens(src)
+}
+
+func (this *CodeShingler) goTokens(src string) []string {
+	var s scanner.Scanner
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+
+	// a nil error handler lets us carry on through
+	// code fragments that don't compile
+	s.Init(file, []byte(src), nil, 0)
+
+	tokens := []string{}
+	for {
+		_, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+
+		switch {
+		case tok == token.SEMICOLON && lit == "\n":
+			// automatically inserted, depends on formatting
+			continue
+		case tok == token.IDENT:
+			tokens = append(tokens, this.ident(lit))
+		case tok.IsLiteral():
+			tokens = append(tokens, this.literal(lit))
+		case lit != "":
+			tokens = append(tokens, lit)
+		default:
+			tokens = append(tokens, tok.String())
+		}
+	}
+
+	return tokens
+}
+
+func (this *CodeShingler) ident(lit string) string {
+	if this.NormalizeIdentifiers {
+		return identPlaceholder
+	}
+	return lit
+}
+
+func (this *CodeShingler) literal(lit string) string {
+	if this.NormalizeLiterals {
+		return literalPlaceholder
+	}
+	return lit
+}
+
+// keywords shared by most C like languages.
+// These are never normalized.
+var clikeKeywords = map[string]bool{
+	"auto": true, "break": true, "case": true, "catch": true, "char": true,
+	"class": true, "const": true, "continue": true, "default": true, "delete": true,
+	"do": true, "double": true, "else": true, "enum": true, "extends": true,
+	"extern": true, "false": true, "finally": true, "float": true, "for": true,
+	"function": true, "goto": true, "if": true, "implements": true, "import": true,
+	"int": true, "interface": true, "let": true, "long": true, "namespace": true,
+	"new": true, "null": true, "package": true, "private": true, "protected": true,
+	"public": true, "return": true, "short": true, "signed": true, "sizeof": true,
+	"static": true, "struct": true, "super": true, "switch": true, "this": true,
+	"throw": true, "throws": true, "true": true, "try": true, "typedef": true,
+	"union": true, "unsigned": true, "using": true, "var": true, "virtual": true,
+	"void": true, "volatile": true, "while": true,
+}
+
+// multi character operators, longest first
+var clikeOperators = []string{
+	">>>=", "<<=", ">>=", ">>>", "===", "!==", "...",
+	"->", "++", "--", "==", "!=", "<=", ">=", "&&", "||", "<<", ">>",
+	"+=", "-=", "*=", "/=", "%=", "&=", "|=", "^=", "::", "=>",
+}
+
+// clikeTokens is a small generic lexer for languages with
+// C like comments, string literals, identifiers and operators
+func (this *CodeShingler) clikeTokens(src string) []string {
+	tokens := []string{}
+
+	for i := 0; i < len(src); {
+		r, size := utf8.DecodeRuneInString(src[i:])
+
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+
+		case strings.HasPrefix(src[i:], "//"):
+			end := strings.IndexByte(src[i:], '\n')
+			if end < 0 {
+				end = len(src) - i
+			}
+			i += end
+
+		case strings.HasPrefix(src[i:], "/*"):
+			end := strings.Index(src[i+2:], "*/")
+			if end < 0 {
+				i = len(src)
+			} else {
+				i += end + 4
+			}
+
+		case r == '"' || r == '\'' || r == '`':
+			end := i + 1
+			for end < len(src) && src[end] != byte(r) {
+				if src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end < len(src) {
+				end++
+			}
+			if end > len(src) {
+				end = len(src)
+			}
+			tokens = append(tokens, this.literal(src[i:end]))
+			i = end
+
+		case unicode.IsDigit(r):
+			end := i + size
+			for end < len(src) && isNumberPart(src[end]) {
+				end++
+			}
+			tokens = append(tokens, this.literal(src[i:end]))
+			i = end
+
+		case isIdentStart(r):
+			end := i + size
+			for end < len(src) {
+				r, size := utf8.DecodeRuneInString(src[end:])
+				if !isIdentStart(r) && !unicode.IsDigit(r) {
+					break
+				}
+				end += size
+			}
+
+			word := src[i:end]
+			if clikeKeywords[word] {
+				tokens = append(tokens, word)
+			} else {
+				tokens = append(tokens, this.ident(word))
+			}
+			i = end
+
+		default:
+			op := src[i : i+size]
+			for _, o := range clikeOperators {
+				if strings.HasPrefix(src[i:], o) {
+					op = o
+					break
+				}
+			}
+			tokens = append(tokens, op)
+			i += len(op)
+		}
+	}
+
+	return tokens
+}
+
+func isIdentStart(r rune) bool {
+	return r == '_' || r == '$' || unicode.IsLetter(r)
+}
+
+// isNumberPart accepts hex digits, suffixes, exponents
+// and decimal points following the first digit
+func isNumberPart(b byte) bool {
+	return b == '.' || b == '_' ||
+		('0' <= b && b <= '9') || ('a' <= b && b <= 'z') || ('A' <= b && b <= 'Z')
+}
@@ -0,0 +1,78 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+const goSource = `package main
+
+// sum adds up the numbers
+func sum(numbers []int) int {
+	total := 0
+	for _, n := range numbers {
+		total += n
+	}
+	return total
+}`
+
+const goClone = `package main
+
+/* renamed, reformatted and recommented */
+func add(xs []int) int {
+	acc := 10
+	for _, x := range xs { acc += x }
+	return acc
+}`
+
+func TestGoTokens(t *testing.T) {
+	sh := NewCodeShingler(5, GoCode)
+	sh.NormalizeIdentifiers = false
+	sh.NormalizeLiterals = false
+
+	tokens := sh.Tokens(`x := foo(bar, "baz") // call`)
+	assert.Equal(t, []string{"x", ":=", "foo", "(", "bar", ",", `"baz"`, ")"}, tokens)
+
+	sh = NewCodeShingler(5, GoCode)
+	tokens = sh.Tokens(`x := foo(bar, "baz")`)
+	assert.Equal(t, []string{"$ID", ":=", "$ID", "(", "$ID", ",", "$LIT", ")"}, tokens)
+}
+
+func TestCLikeTokens(t *testing.T) {
+	sh := NewCodeShingler(5, CLikeCode)
+	sh.NormalizeIdentifiers = false
+	sh.NormalizeLiterals = false
+
+	src := `/* header */ for (int i = 0x1F; i <= n; i++) { s += "a\"b"; } // done`
+	tokens := sh.Tokens(src)
+	assert.Equal(t, []string{"for", "(", "int", "i", "=", "0x1F", ";", "i", "<=", "n", ";", "i", "++", ")",
+		"{", "s", "+=", `"a\"b"`, ";", "}"}, tokens)
+
+	sh = NewCodeShingler(5, CLikeCode)
+	assert.Equal(t, []string{"return", "$ID", "(", "$LIT", ")", ";"}, sh.Tokens("return foo('c');"))
+}
+
+func TestCodeCloneSimilarity(t *testing.T) {
+	sh := NewCodeShingler(4, GoCode)
+
+	// renamed clones are identical once normalized
+	assert.Equal(t, 1.0, JaccardDistance(NewWordSetFromTextWith(goSource, sh), NewWordSetFromTextWith(goClone, sh)))
+	assert.Equal(t, GenerateMinHashWith(goSource, sh), GenerateMinHashWith(goClone, sh))
+
+	// but not when identifiers are kept
+	sh.NormalizeIdentifiers = false
+	assert.True(t, JaccardDistance(NewWordSetFromTextWith(goSource, sh), NewWordSetFromTextWith(goClone, sh)) < 0.5)
+
+	// prose tokenization can't see the clone at all
+	assert.False(t, Similar(goSource, goClone))
+}
+
+func TestCodeShinglerK(t *testing.T) {
+	assert.Panics(t, func() { NewCodeShingler(0, GoCode) })
+	assert.Panics(t, func() { NewCodeShingler(-1, CLikeCode) })
+
+	// without shingles unrelated snippets aren't similar
+	sh := &CodeShingler{K: 0, Language: GoCode}
+	assert.Equal(t, []string{}, sh.Shingles("x := 1"))
+	assert.Equal(t, 0.0, Jaccard.Similarity(NewWordSetFromTextWith("x := 1", sh), NewWordSetFromTextWith("for {}", sh)))
+}
@@ -0,0 +1,118 @@
+package minhash
+
+import (
+	"hash/fnv"
+	"sort"
+	"strings"
+)
+
+// CompactWordSet is a memory compact WordSet. It stores the
+// sorted 64 bit hashes of its lower cased words instead of the
+// words, 8 bytes per member instead of a map entry and a string,
+// and intersects and unions by merging the sorted hashes.
+//
+// Hashes can't be turned back into words, see Members for
+// going back to a WordSet.
+type CompactWordSet struct {
+	id     DocID
+	hashes []uint64
+}
+
+// hash64 hashes a lower cased word to 64 bits,
+// where collisions are too rare to matter
+func hash64(word string) uint64 {
+	h := fnv.New64a()
+	h.Write([]byte(word))
+
+	return h.Sum64()
+}
+
+// newCompactWordSet sorts and dedups the hashes
+func newCompactWordSet(id DocID, hashes []uint64) *CompactWordSet {
+	return &CompactWordSet{id: id, hashes: sortedSet(hashes)}
+}
+
+func NewCompactWordSetFromText(text string) *CompactWordSet {
+	return NewCompactWordSetFromTextWith(text, DefaultShingler)
+}
+
+// NewCompactWordSetFromTextWith builds a CompactWordSet holding
+// the shingles produced by the given Shingler
+func NewCompactWordSetFromTextWith(text string, sh Shingler) *CompactWordSet {
+	shingles := sh.Shingles(text)
+	hashes := make([]uint64, 0, len(shingles))
+
+	for _, s := range shingles {
+		hashes = append(hashes, hash64(strings.ToLower(s)))
+	}
+	return newCompactWordSet("", hashes)
+}
+
+// Compact converts the WordSet into a CompactWordSet
+func (this *WordSet) Compact() *CompactWordSet {
+	hashes := make([]uint64, 0, this.Len())
+
+	for word := range this.membership {
+		hashes = append(hashes, hash64(word))
+	}
+	return newCompactWordSet(this.id, hashes)
+}
+
+// Members returns the words of candidates that are in the set.
+// Pass the WordSet the set was compacted from (or a vocabulary)
+// to get the string keyed form back.
+func (this *CompactWordSet) Members(candidates *WordSet) *WordSet {
+	ws := NewWordSet()
+	ws.id = this.id
+
+	for word := range candidates.membership {
+		if this.Contains(word) {
+			ws.membership[word] = struct{}{}
+		}
+	}
+	return ws
+}
+
+// ID of the document the set was built from
+func (this *CompactWordSet) ID() DocID {
+	return this.id
+}
+
+func (this *CompactWordSet) SetID(id DocID) {
+	this.id = id
+}
+
+func (this *CompactWordSet) Len() int {
+	return len(this.hashes)
+}
+
+func (this *CompactWordSet) Contains(word string) bool {
+	h := hash64(strings.ToLower(word))
+	i := sort.Search(len(this.hashes), func(i int) bool { return this.hashes[i] >= h })
+
+	return i < len(this.hashes) && this.hashes[i] == h
+}
+
+// Intersection counts the members of both sets
+// by merging the sorted hashes
+func (this *CompactWordSet) Intersection(other *CompactWordSet) int {
+	return mergeOverlap(this.hashes, other.hashes)
+}
+
+// IntersectionSet returns the members of both sets
+func (this *CompactWordSet) IntersectionSet(other *CompactWordSet) *CompactWordSet {
+	return &CompactWordSet{hashes: mergeIntersection(this.hashes, other.hashes)}
+}
+
+// Union returns the members of either set
+func (this *CompactWordSet) Union(other *CompactWordSet) *CompactWordSet {
+	return &CompactWordSet{hashes: mergeUnion(this.hashes, other.hashes)}
+}
+
+// CompactJaccard is JaccardDistance for CompactWordSets
+func CompactJaccard(left, right *CompactWordSet) float64 {
+	intersection := float64(left.Intersection(right))
+	union := float64(left.Len()+right.Len()) - intersection
+
+	return ratio(intersection, union)
+}
@@ -0,0 +1,129 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCompactWordSet(t *testing.T) {
+	a := newSet("a", "b", "c", "d").Compact()
+	b := newSet("c", "d", "e").Compact()
+
+	assert.Equal(t, 4, a.Len())
+	assert.True(t, a.Contains("A"))
+	assert.False(t, a.Contains("e"))
+
+	assert.Equal(t, 2, a.Intersection(b))
+	assert.Equal(t, 2, a.IntersectionSet(b).Len())
+	assert.True(t, a.IntersectionSet(b).Contains("c"))
+	assert.Equal(t, 5, a.Union(b).Len())
+	assert.True(t, a.Union(b).Contains("e"))
+	assert.Equal(t, 2.0/5.0, CompactJaccard(a, b))
+	assert.Equal(t, 0.0, CompactJaccard(NewWordSet().Compact(), NewWordSet().Compact()))
+}
+
+func TestCompactWordSetConversion(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume Excellent job opportunity!"
+	ws := NewWordSetFromDoc("job-1", s)
+
+	compact := ws.Compact()
+	assert.Equal(t, DocID("job-1"), compact.ID())
+	assert.Equal(t, ws.Len(), compact.Len())
+	assert.Equal(t, ws.Len(), NewCompactWordSetFromText(s).Len())
+
+	back := compact.Members(ws)
+	assert.True(t, back.Equal(ws))
+	assert.Equal(t, DocID("job-1"), back.ID())
+
+	other := "Excellent job opportunity! need ASP.NET, MySQL and resume"
+	assert.Equal(t, JaccardSimilarity(s, other), CompactJaccard(NewCompactWordSetFromText(s), NewCompactWordSetFromText(other)))
+}
+
+func longDocument(seed int) string {
+	words := []string{}
+	for i := 0; i < 5000; i++ {
+		words = append(words, "w"+strconv.Itoa((i*7+seed)%1500))
+	}
+	return strings.Join(words, " ")
+}
+
+// fixedShingler returns the same shingles for any text,
+// so every form is built from exactly the same input
+type fixedShingler []string
+
+func (this fixedShingler) Shingles(text string) []string {
+	return this
+}
+
+func (this fixedShingler) Hashes(text string) []uint32 {
+	return HashShingles(this)
+}
+
+// benchmarkBuild builds b.N sets, keeps them all and reports
+// the heap they retain per set, which -benchmem can't show
+// since it counts every allocation made while building
+func benchmarkBuild(b *testing.B, build func() interface{}) {
+	sets := make([]interface{}, b.N)
+	var before, after runtime.MemStats
+
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := range sets {
+		sets[i] = build()
+	}
+
+	b.StopTimer()
+	runtime.GC()
+	runtime.ReadMemStats(&after)
+
+	retained := int64(after.HeapAlloc) - int64(before.HeapAlloc)
+	b.ReportMetric(float64(retained)/float64(b.N), "retained-B/set")
+	runtime.KeepAlive(sets)
+}
+
+// go test -bench Build shows the memory retained by each
+// form of the same document and the time to build it.
+// The WordSet shares its strings with the shingles here,
+// built from text it also keeps the shingle strings.
+
+func BenchmarkWordSetBuild(b *testing.B) {
+	sh := fixedShingler(DefaultShingler.Shingles(longDocument(0)))
+
+	benchmarkBuild(b, func() interface{} {
+		return NewWordSetFromTextWith("", sh)
+	})
+}
+
+func BenchmarkCompactWordSetBuild(b *testing.B) {
+	sh := fixedShingler(DefaultShingler.Shingles(longDocument(0)))
+
+	benchmarkBuild(b, func() interface{} {
+		return NewCompactWordSetFromTextWith("", sh)
+	})
+}
+
+func BenchmarkWordSetIntersection(b *testing.B) {
+	left := NewWordSetFromText(longDocument(0))
+	right := NewWordSetFromText(longDocument(1))
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		left.Intersection(right)
+	}
+}
+
+func BenchmarkCompactWordSetIntersection(b *testing.B) {
+	left := NewWordSetFromText(longDocument(0)).Compact()
+	right := NewWordSetFromText(longDocument(1)).Compact()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		left.Intersection(right)
+	}
+}
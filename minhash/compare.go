@@ -0,0 +1,94 @@
+package minhash
+
+// Comparator decides whether two documents are similar enough.
+// The same threshold and comparison operator are used for the
+// exact (Jaccard) and the approximate (MinHash) predicates, so
+// both paths agree at the boundary.
+//
+// With Inclusive a similarity equal to Threshold is similar (>=),
+// without it the similarity has to be above Threshold (>).
+// Measure is the exact measure used for WordSets, Jaccard when nil.
+type Comparator struct {
+	Threshold float64
+	Inclusive bool
+	Measure   Measure
+}
+
+// The package level predicates keep their historical operators:
+// Similar and SimilarWordSets use >=, StringsSimilar and
+// MinHashSimilar use >. New code should use a Comparator.
+var (
+	exactComparator   = NewComparator(SimilarityThreshold, true)
+	minHashComparator = NewComparator(SimilarityThreshold, false)
+)
+
+// NewComparator returns a Comparator using the Jaccard index
+func NewComparator(threshold float64, inclusive bool) *Comparator {
+	return &Comparator{Threshold: threshold, Inclusive: inclusive, Measure: Jaccard}
+}
+
+// Passes reports whether a similarity clears the threshold
+func (this *Comparator) Passes(similarity float64) bool {
+	if this.Inclusive {
+		return similarity >= this.Threshold
+	}
+	return similarity > this.Threshold
+}
+
+func (this *Comparator) measure() Measure {
+	if this.Measure == nil {
+		return Jaccard
+	}
+	return this.Measure
+}
+
+// Similar compares two strings exactly
+func (this *Comparator) Similar(left, right string) bool {
+	return this.SimilarWordSets(NewWordSetFromText(left), NewWordSetFromText(right))
+}
+
+// SimilarWordSets compares two WordSets exactly
+func (this *Comparator) SimilarWordSets(left, right *WordSet) bool {
+	return this.Passes(this.measure().Similarity(left, right))
+}
+
+// StringsSimilar compares two strings by their MinHash signatures
+func (this *Comparator) StringsSimilar(left, right string) bool {
+	return this.SimilarMinHashes(GenerateMinHash(left), GenerateMinHash(right))
+}
+
+// MinHashSimilar compares two signatures in their string form,
+// see MinHash.Str. Signatures that don't parse aren't similar.
+func (this *Comparator) MinHashSimilar(left, right string) bool {
+	l, err := MinHashFromStr(left)
+	if err != nil {
+		return false
+	}
+	r, err := MinHashFromStr(right)
+	if err != nil {
+		return false
+	}
+	return this.SimilarMinHashes(l, r)
+}
+
+// SimilarMinHashes compares two signatures. Signatures
+// of different lengths can't be compared and aren't similar.
+func (this *Comparator) SimilarMinHashes(left, right MinHash) bool {
+	if len(left) != len(right) {
+		return false
+	}
+	return this.Passes(minHashSimilarity(left, right))
+}
+
+// Explain explains the similarity of two documents against
+// the threshold of the Comparator, see ExplainWith
+func (this *Comparator) Explain(left, right string) *Explanation {
+	return this.ExplainWith(left, right, DefaultShingler)
+}
+
+func (this *Comparator) ExplainWith(left, right string, sh Shingler) *Explanation {
+	e := ExplainWith(left, right, sh)
+	e.Threshold = this.Threshold
+
+	return e
+}
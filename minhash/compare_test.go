@@ -0,0 +1,106 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+)
+
+func TestComparatorPasses(t *testing.T) {
+	inclusive := NewComparator(0.8, true)
+	exclusive := NewComparator(0.8, false)
+
+	assert.True(t, inclusive.Passes(0.8))
+	assert.False(t, exclusive.Passes(0.8))
+	assert.True(t, exclusive.Passes(0.85))
+	assert.False(t, inclusive.Passes(0.75))
+}
+
+func TestComparatorBoundary(t *testing.T) {
+	// exactly 0.8 similar, both ways
+	l := MinHash{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20}
+	r := MinHash{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 0, 0, 0, 0}
+	left := newSet("1", "2", "3", "4", "5")
+	right := newSet("1", "2", "3", "4")
+
+	for _, c := range []*Comparator{NewComparator(0.8, true), NewComparator(0.8, false)} {
+		// exact and MinHash predicates agree
+		assert.Equal(t, c.SimilarWordSets(left, right), c.SimilarMinHashes(l, r))
+		assert.Equal(t, c.SimilarMinHashes(l, r), c.MinHashSimilar(l.Str(), r.Str()))
+	}
+	assert.True(t, NewComparator(0.8, true).SimilarMinHashes(l, r))
+	assert.False(t, NewComparator(0.8, false).SimilarWordSets(left, right))
+
+	assert.False(t, NewComparator(0.0, true).MinHashSimilar("not a signature", l.Str()))
+}
+
+func TestComparatorSignatureLengths(t *testing.T) {
+	c := NewComparator(0.5, true)
+
+	assert.False(t, c.SimilarMinHashes(MinHash{1, 2, 3}, MinHash{1}))
+	assert.False(t, NewComparator(0, true).SimilarMinHashes(MinHash{1, 2, 3}, MinHash{1}))
+	assert.True(t, c.SimilarMinHashes(MinHash{1, 2, 3}, MinHash{1, 2, 0}))
+	assert.Equal(t, 0.0, minHashSimilarity(MinHash{}, MinHash{}))
+
+	// malformed signatures aren't similar, even
+	// when the part that parses is the same
+	assert.False(t, MinHashSimilar("5 x", "5 y"))
+	assert.False(t, MinHashSimilar("x", "x"))
+	assert.False(t, c.MinHashSimilar("5 x", "5 y"))
+
+	// strings that parse but aren't signatures of the same length
+	assert.False(t, MinHashSimilar("1 2 3", "1"))
+	assert.False(t, c.MinHashSimilar("1 2 3", "1"))
+}
+
+func TestComparatorExplain(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume"
+
+	assert.Equal(t, SimilarityThreshold, Explain(s, s).Threshold)
+	assert.Contains(t, Explain(s, s).Report(), "(threshold 0.799)")
+
+	e := NewComparator(0.5, true).Explain(s, s)
+	assert.Equal(t, 0.5, e.Threshold)
+	assert.Contains(t, e.Report(), "(threshold 0.500)")
+}
+
+func TestComparatorDefaults(t *testing.T) {
+	// existing callers keep their operators
+	left := newSet("1", "2", "3", "4", "5")
+
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume"
+	assert.True(t, Similar(s, s))
+	assert.True(t, StringsSimilar(s, s))
+
+	// exactly at the threshold, 799 of 1000
+	words := []string{}
+	lmh := MinHash{}
+	rmh := MinHash{}
+	for i := 0; i < 1000; i++ {
+		words = append(words, strconv.Itoa(i))
+		lmh = append(lmh, i)
+		if i < 799 {
+			rmh = append(rmh, i)
+		} else {
+			rmh = append(rmh, -1)
+		}
+	}
+	atLeft := newSet(words...)
+	atRight := newSet(words[:799]...)
+	assert.Equal(t, SimilarityThreshold, JaccardDistance(atLeft, atRight))
+	assert.Equal(t, SimilarityThreshold, minHashSimilarity(lmh, rmh))
+
+	// SimilarWordSets uses >=, MinHashSimilar uses >
+	assert.True(t, SimilarWordSets(atLeft, atRight))
+	assert.False(t, MinHashSimilar(lmh.Str(), rmh.Str()))
+	assert.True(t, MinHashSimilar(lmh.Str(), lmh.Str()))
+
+	// a different threshold and measure per product surface
+	strict := NewComparator(0.95, true)
+	loose := &Comparator{Threshold: 0.5, Inclusive: true, Measure: Overlap}
+	right := newSet("1", "2", "3", "4")
+
+	assert.False(t, strict.SimilarWordSets(left, right))
+	assert.True(t, loose.SimilarWordSets(left, right))
+	assert.True(t, (&Comparator{Threshold: 0.5}).SimilarWordSets(left, right))
+}
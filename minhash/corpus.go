@@ -0,0 +1,127 @@
+package minhash
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+)
+
+// CorpusStats counts in how many documents of a corpus
+// sample every shingle appears (its document frequency).
+//
+// Shingles that show up in a large share of documents are
+// boilerplate: legal footers, EEO statements and other text
+// added by the applicant tracking system. They make unrelated
+// documents look similar, so a BoilerplateShingler drops them.
+//
+// CorpusStats is serialized with Save and LoadCorpusStats so the
+// same suppression list is used at signing and at query time.
+type CorpusStats struct {
+	Docs int            `json:"docs"`
+	DF   map[uint32]int `json:"df"`
+}
+
+func NewCorpusStats() *CorpusStats {
+	var stats CorpusStats
+	stats.DF = map[uint32]int{}
+
+	return &stats
+}
+
+// Add counts the shingles of a document
+// shingled with the DefaultShingler
+func (this *CorpusStats) Add(text string) {
+	this.AddWith(text, DefaultShingler)
+}
+
+// AddWith counts the shingles of a document
+// shingled with the given Shingler
+func (this *CorpusStats) AddWith(text string, sh Shingler) {
+	this.AddHashes(sh.Hashes(text))
+}
+
+// AddHashes counts one document given its shingle hashes.
+// Repeated shingles are only counted once.
+func (this *CorpusStats) AddHashes(hashes []uint32) {
+	seen := map[uint32]bool{}
+
+	for _, h := range hashes {
+		if !seen[h] {
+			seen[h] = true
+			this.DF[h] += 1
+		}
+	}
+	this.Docs += 1
+}
+
+// DocumentFrequency is the share of documents
+// that contain the shingle, between 0 and 1
+func (this *CorpusStats) DocumentFrequency(h uint32) float64 {
+	if this.Docs == 0 {
+		return 0
+	}
+	return float64(this.DF[h]) / float64(this.Docs)
+}
+
+// IsBoilerplate reports whether the shingle appears in
+// more than maxDF of the documents
+func (this *CorpusStats) IsBoilerplate(h uint32, maxDF float64) bool {
+	return this.DocumentFrequency(h) > maxDF
+}
+
+// IDF is the smoothed inverse document frequency of a shingle,
+// ln((1 + docs) / (1 + df)) + 1. Rare shingles weigh more and
+// shingles never seen in the corpus get the highest weight.
+func (this *CorpusStats) IDF(h uint32) float64 {
+	return math.Log(float64(1+this.Docs)/float64(1+this.DF[h])) + 1
+}
+
+// Save writes the stats to w as JSON
+func (this *CorpusStats) Save(w io.Writer) error {
+	return json.NewEncoder(w).Encode(this)
+}
+
+// LoadCorpusStats reads stats written by Save
+func LoadCorpusStats(r io.Reader) (*CorpusStats, error) {
+	stats := NewCorpusStats()
+
+	if err := json.NewDecoder(r).Decode(stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// BoilerplateShingler wraps a Shingler and drops every shingle
+// whose document frequency in Stats is above MaxDF. Use it
+// with NewWordSetFromTextWith and GenerateMinHashWith.
+type BoilerplateShingler struct {
+	Shingler Shingler
+	Stats    *CorpusStats
+	MaxDF    float64
+}
+
+func NewBoilerplateShingler(sh Shingler, stats *CorpusStats, maxDF float64) *BoilerplateShingler {
+	return &BoilerplateShingler{Shingler: sh, Stats: stats, MaxDF: maxDF}
+}
+
+func (this *BoilerplateShingler) Shingles(text string) []string {
+	shingles := []string{}
+
+	for _, s := range this.Shingler.Shingles(text) {
+		if !this.Stats.IsBoilerplate(shingleHash(s), this.MaxDF) {
+			shingles = append(shingles, s)
+		}
+	}
+	return shingles
+}
+
+func (this *BoilerplateShingler) Hashes(text string) []uint32 {
+	hashes := []uint32{}
+
+	for _, h := range this.Shingler.Hashes(text) {
+		if !this.Stats.IsBoilerplate(h, this.MaxDF) {
+			hashes = append(hashes, h)
+		}
+	}
+	return hashes
+}
@@ -0,0 +1,59 @@
+package minhash
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+const eeoFooter = "We are an equal opportunity employer and all qualified applicants will receive consideration for employment without regard to race, color, religion, sex, national origin, disability status or protected veteran status."
+
+func TestCorpusStats(t *testing.T) {
+	stats := NewCorpusStats()
+	stats.Add("a b c a b c")
+	stats.Add("a b c d")
+	stats.Add("x y z")
+
+	assert.Equal(t, 3, stats.Docs)
+	assert.Equal(t, 2.0/3.0, stats.DocumentFrequency(hash("a b c")))
+	assert.Equal(t, 1.0/3.0, stats.DocumentFrequency(hash("x y z")))
+	assert.Equal(t, 0.0, stats.DocumentFrequency(hash("not seen here")))
+
+	assert.True(t, stats.IsBoilerplate(hash("a b c"), 0.5))
+	assert.False(t, stats.IsBoilerplate(hash("x y z"), 0.5))
+	assert.False(t, NewCorpusStats().IsBoilerplate(hash("a b c"), 0.5))
+}
+
+func TestCorpusStatsSave(t *testing.T) {
+	stats := NewCorpusStats()
+	stats.Add("Excellent job opportunity! need Node.js")
+
+	var buf bytes.Buffer
+	assert.Nil(t, stats.Save(&buf))
+
+	loaded, err := LoadCorpusStats(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, stats, loaded)
+
+	_, err = LoadCorpusStats(strings.NewReader("not json"))
+	assert.NotNil(t, err)
+}
+
+func TestBoilerplateShingler(t *testing.T) {
+	left := "Line cook wanted for a busy downtown brunch spot, weekend shifts. " + eeoFooter
+	right := "Senior accountant to own month end close and audits for our firm. " + eeoFooter
+
+	stats := NewCorpusStats()
+	stats.Add(left)
+	stats.Add(right)
+	stats.Add("Delivery driver with a clean record, own car required. " + eeoFooter)
+	stats.Add("Barista for a specialty coffee bar, no experience needed.")
+
+	assert.True(t, JaccardSimilarity(left, right) > 0.5)
+
+	sh := NewBoilerplateShingler(DefaultShingler, stats, 0.5)
+	assert.Equal(t, 0.0, JaccardDistance(NewWordSetFromTextWith(left, sh), NewWordSetFromTextWith(right, sh)))
+	assert.Equal(t, HashShingles(sh.Shingles(left)), sh.Hashes(left))
+	assert.Equal(t, 11, len(sh.Shingles(left)))
+}
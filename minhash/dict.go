@@ -0,0 +1,301 @@
+package minhash
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Dictionary interns shingles to small integer ids so that
+// a shingle shared by many documents is stored once, instead
+// of once in every WordSet holding it. Ids are handed out
+// in the order words are first seen, starting at 0.
+//
+// A Dictionary is safe for concurrent use, documents can be
+// interned by several goroutines against the same one.
+type Dictionary struct {
+	mu    sync.RWMutex
+	ids   map[string]uint32
+	words []string
+}
+
+func NewDictionary() *Dictionary {
+	var dict Dictionary
+	dict.ids = map[string]uint32{}
+
+	return &dict
+}
+
+// Intern returns the id of a word, adding
+// the word to the dictionary if it's new
+func (this *Dictionary) Intern(word string) uint32 {
+	word = strings.ToLower(word)
+
+	if id, ok := this.Lookup(word); ok {
+		return id
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	// someone else may have added it
+	// while we were waiting for the lock
+	if id, ok := this.ids[word]; ok {
+		return id
+	}
+
+	id := uint32(len(this.words))
+	this.ids[word] = id
+	this.words = append(this.words, word)
+
+	return id
+}
+
+// Lookup returns the id of a word without adding it
+func (this *Dictionary) Lookup(word string) (uint32, bool) {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	id, ok := this.ids[strings.ToLower(word)]
+	return id, ok
+}
+
+// Word returns the word interned as id
+func (this *Dictionary) Word(id uint32) (string, bool) {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	if int(id) >= len(this.words) {
+		return "", false
+	}
+	return this.words[id], true
+}
+
+// Len returns the number of words in the dictionary
+func (this *Dictionary) Len() int {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	return len(this.words)
+}
+
+// InternedWordSet is a WordSet whose members are the sorted
+// ids of its words in a shared Dictionary, 4 bytes per member.
+// Set operations merge the sorted ids and never touch strings.
+//
+// An InternedWordSet isn't modified once built so it's safe
+// for concurrent reads. Sets built on different dictionaries
+// can still be compared, the ids are translated through the
+// words first, which is a lot slower. Only Union and Save add
+// the words of the other set to the receiver's dictionary.
+type InternedWordSet struct {
+	id   DocID
+	dict *Dictionary
+	ids  []uint32
+}
+
+// newInternedWordSet sorts and dedups the ids
+func newInternedWordSet(id DocID, dict *Dictionary, ids []uint32) *InternedWordSet {
+	return &InternedWordSet{id: id, dict: dict, ids: sortedSet(ids)}
+}
+
+func (this *Dictionary) NewWordSetFromText(text string) *InternedWordSet {
+	return this.NewWordSetFromTextWith(text, DefaultShingler)
+}
+
+// NewWordSetFromTextWith interns the shingles produced
+// by the given Shingler into an InternedWordSet
+func (this *Dictionary) NewWordSetFromTextWith(text string, sh Shingler) *InternedWordSet {
+	shingles := sh.Shingles(text)
+	ids := make([]uint32, 0, len(shingles))
+
+	for _, s := range shingles {
+		ids = append(ids, this.Intern(s))
+	}
+	return newInternedWordSet("", this, ids)
+}
+
+// InternWordSet converts a WordSet into an InternedWordSet,
+// adding its words to the dictionary
+func (this *Dictionary) InternWordSet(ws *WordSet) *InternedWordSet {
+	ids := make([]uint32, 0, ws.Len())
+
+	for word := range ws.membership {
+		ids = append(ids, this.Intern(word))
+	}
+	return newInternedWordSet(ws.id, this, ids)
+}
+
+// ID of the document the set was built from
+func (this *InternedWordSet) ID() DocID {
+	return this.id
+}
+
+func (this *InternedWordSet) SetID(id DocID) {
+	this.id = id
+}
+
+// Dictionary the set's ids refer to
+func (this *InternedWordSet) Dictionary() *Dictionary {
+	return this.dict
+}
+
+func (this *InternedWordSet) Len() int {
+	return len(this.ids)
+}
+
+func (this *InternedWordSet) Contains(word string) bool {
+	id, ok := this.dict.Lookup(word)
+	if !ok {
+		return false
+	}
+
+	i := sort.Search(len(this.ids), func(i int) bool { return this.ids[i] >= id })
+	return i < len(this.ids) && this.ids[i] == id
+}
+
+// WordSet converts the set back into a plain WordSet
+func (this *InternedWordSet) WordSet() *WordSet {
+	ws := NewWordSet()
+	ws.id = this.id
+
+	for _, id := range this.ids {
+		word, _ := this.dict.Word(id)
+		ws.membership[word] = struct{}{}
+	}
+	return ws
+}
+
+// idsIn returns the ids of the set in dict, translating
+// them if dict is a different dictionary. Words dict doesn't
+// have are dropped, they can't be in a set built on dict, so
+// comparing sets doesn't grow the dictionary.
+func (this *InternedWordSet) idsIn(dict *Dictionary) []uint32 {
+	if this.dict == dict {
+		return this.ids
+	}
+
+	ids := make([]uint32, 0, len(this.ids))
+	for _, id := range this.ids {
+		word, _ := this.dict.Word(id)
+		if id, ok := dict.Lookup(word); ok {
+			ids = append(ids, id)
+		}
+	}
+	return sortedSet(ids)
+}
+
+// internIn is idsIn adding the words dict doesn't have
+func (this *InternedWordSet) internIn(dict *Dictionary) []uint32 {
+	if this.dict == dict {
+		return this.ids
+	}
+
+	ids := make([]uint32, 0, len(this.ids))
+	for _, id := range this.ids {
+		word, _ := this.dict.Word(id)
+		ids = append(ids, dict.Intern(word))
+	}
+	return sortedSet(ids)
+}
+
+// Intersection counts the members of both sets
+// by merging the sorted ids
+func (this *InternedWordSet) Intersection(other *InternedWordSet) int {
+	return mergeOverlap(this.ids, other.idsIn(this.dict))
+}
+
+// IntersectionSet returns the members of both sets
+func (this *InternedWordSet) IntersectionSet(other *InternedWordSet) *InternedWordSet {
+	return &InternedWordSet{dict: this.dict, ids: mergeIntersection(this.ids, other.idsIn(this.dict))}
+}
+
+// Union returns the members of either set. Words of a set
+// built on another dictionary are added to this one.
+func (this *InternedWordSet) Union(other *InternedWordSet) *InternedWordSet {
+	return &InternedWordSet{dict: this.dict, ids: mergeUnion(this.ids, other.internIn(this.dict))}
+}
+
+// Difference returns the members of this set
+// that aren't in the other one
+func (this *InternedWordSet) Difference(other *InternedWordSet) *InternedWordSet {
+	return &InternedWordSet{dict: this.dict, ids: mergeDifference(this.ids, other.idsIn(this.dict))}
+}
+
+// InternedJaccard is JaccardDistance for InternedWordSets
+func InternedJaccard(left, right *InternedWordSet) float64 {
+	intersection := float64(left.Intersection(right))
+	union := float64(left.Len()+right.Len()) - intersection
+
+	return ratio(intersection, union)
+}
+
+// the JSON form written by Save, the words
+// once and every set as ids into them
+type dictionaryFile struct {
+	Words []string        `json:"words"`
+	Sets  []internedEntry `json:"sets"`
+}
+
+type internedEntry struct {
+	ID  DocID    `json:"id"`
+	IDs []uint32 `json:"ids"`
+}
+
+// Save writes the dictionary and sets built on it to w as JSON.
+// Sets built on another dictionary are translated (and their
+// words interned) first.
+func (this *Dictionary) Save(w io.Writer, sets []*InternedWordSet) error {
+	file := dictionaryFile{Sets: make([]internedEntry, 0, len(sets))}
+
+	for _, ws := range sets {
+		file.Sets = append(file.Sets, internedEntry{ID: ws.id, IDs: ws.internIn(this)})
+	}
+
+	this.mu.RLock()
+	file.Words = this.words
+	err := json.NewEncoder(w).Encode(file)
+	this.mu.RUnlock()
+
+	return err
+}
+
+var (
+	errInternedID  = errors.New("minhash: set refers to a word missing from the dictionary")
+	errDuplicateID = errors.New("minhash: dictionary holds a word twice")
+)
+
+// LoadDictionary reads a dictionary and its sets written by Save
+func LoadDictionary(r io.Reader) (*Dictionary, []*InternedWordSet, error) {
+	var file dictionaryFile
+
+	if err := json.NewDecoder(r).Decode(&file); err != nil {
+		return nil, nil, err
+	}
+
+	// ids are positions in the file, a word appearing
+	// twice (in any case) would make them ambiguous
+	dict := NewDictionary()
+	for i, word := range file.Words {
+		word = strings.ToLower(word)
+		if _, ok := dict.ids[word]; ok {
+			return nil, nil, errDuplicateID
+		}
+		dict.ids[word] = uint32(i)
+		dict.words = append(dict.words, word)
+	}
+
+	sets := make([]*InternedWordSet, 0, len(file.Sets))
+	for _, entry := range file.Sets {
+		for _, id := range entry.IDs {
+			if int(id) >= dict.Len() {
+				return nil, nil, errInternedID
+			}
+		}
+		sets = append(sets, newInternedWordSet(entry.ID, dict, entry.IDs))
+	}
+	return dict, sets, nil
+}
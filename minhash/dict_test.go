@@ -0,0 +1,160 @@
+package minhash
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestDictionary(t *testing.T) {
+	dict := NewDictionary()
+
+	assert.Equal(t, uint32(0), dict.Intern("a b c"))
+	assert.Equal(t, uint32(1), dict.Intern("b c d"))
+	assert.Equal(t, uint32(0), dict.Intern("A B C"))
+	assert.Equal(t, 2, dict.Len())
+
+	id, ok := dict.Lookup("B C D")
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1), id)
+
+	_, ok = dict.Lookup("c d e")
+	assert.False(t, ok)
+	assert.Equal(t, 2, dict.Len())
+
+	word, ok := dict.Word(1)
+	assert.True(t, ok)
+	assert.Equal(t, "b c d", word)
+
+	_, ok = dict.Word(2)
+	assert.False(t, ok)
+}
+
+func TestInternedWordSet(t *testing.T) {
+	dict := NewDictionary()
+	a := dict.InternWordSet(newSet("a", "b", "c", "d"))
+	b := dict.InternWordSet(newSet("c", "d", "e"))
+
+	assert.Equal(t, 5, dict.Len())
+	assert.Equal(t, 4, a.Len())
+	assert.True(t, a.Contains("A"))
+	assert.False(t, a.Contains("e"))
+	assert.False(t, a.Contains("z"))
+
+	assert.Equal(t, 2, a.Intersection(b))
+	assert.True(t, a.IntersectionSet(b).WordSet().Equal(newSet("c", "d")))
+	assert.True(t, a.Union(b).WordSet().Equal(newSet("a", "b", "c", "d", "e")))
+	assert.True(t, a.Difference(b).WordSet().Equal(newSet("a", "b")))
+	assert.Equal(t, 2.0/5.0, InternedJaccard(a, b))
+	assert.Equal(t, 0.0, InternedJaccard(dict.InternWordSet(NewWordSet()), dict.InternWordSet(NewWordSet())))
+
+	// sets on another dictionary are translated
+	other := NewDictionary()
+	c := other.InternWordSet(newSet("e", "d", "c"))
+	assert.Equal(t, 2, a.Intersection(c))
+	assert.True(t, a.Union(c).WordSet().Equal(newSet("a", "b", "c", "d", "e")))
+	assert.Equal(t, InternedJaccard(a, b), InternedJaccard(c, a))
+
+	// comparing doesn't grow the dictionary, only Union does
+	foreign := other.InternWordSet(newSet("x", "y", "z", "a"))
+	assert.Equal(t, 1, a.Intersection(foreign))
+	assert.Equal(t, 1.0/7.0, InternedJaccard(a, foreign))
+	assert.True(t, a.IntersectionSet(foreign).WordSet().Equal(newSet("a")))
+	assert.True(t, a.Difference(foreign).WordSet().Equal(newSet("b", "c", "d")))
+	assert.Equal(t, 5, dict.Len())
+
+	assert.True(t, a.Union(foreign).WordSet().Equal(newSet("a", "b", "c", "d", "x", "y", "z")))
+	assert.Equal(t, 8, dict.Len())
+}
+
+func TestInternedWordSetFromText(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume Excellent job opportunity!"
+	other := "Excellent job opportunity! need ASP.NET, MySQL and resume"
+
+	dict := NewDictionary()
+	a := dict.NewWordSetFromText(s)
+	b := dict.NewWordSetFromText(other)
+	a.SetID("job-1")
+
+	ws := a.WordSet()
+	assert.True(t, ws.Equal(NewWordSetFromText(s)))
+	assert.Equal(t, DocID("job-1"), ws.ID())
+
+	// shared shingles are only stored once
+	assert.Equal(t, a.Len()+b.Len()-a.Intersection(b), dict.Len())
+	assert.Equal(t, JaccardSimilarity(s, other), InternedJaccard(a, b))
+}
+
+func TestDictionarySaveLoad(t *testing.T) {
+	dict := NewDictionary()
+	a := dict.NewWordSetFromText("Excellent job opportunity! need Node.js, MYSQL and resume")
+	b := dict.NewWordSetFromText("Excellent job opportunity! need ASP.NET, MySQL and resume")
+	a.SetID("a")
+	b.SetID("b")
+
+	var buf bytes.Buffer
+	assert.Nil(t, dict.Save(&buf, []*InternedWordSet{a, b}))
+
+	loaded, sets, err := LoadDictionary(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, dict.Len(), loaded.Len())
+	assert.Equal(t, 2, len(sets))
+	assert.Equal(t, DocID("b"), sets[1].ID())
+	assert.Equal(t, loaded, sets[0].Dictionary())
+	assert.True(t, sets[0].WordSet().Equal(a.WordSet()))
+	assert.Equal(t, InternedJaccard(a, b), InternedJaccard(sets[0], sets[1]))
+
+	_, _, err = LoadDictionary(bytes.NewBufferString(`{"words": ["a"], "sets": [{"id": "x", "ids": [0, 1]}]}`))
+	assert.NotNil(t, err)
+
+	// ids aren't renumbered around duplicates
+	_, _, err = LoadDictionary(bytes.NewBufferString(`{"words": ["a", "a", "b", "c"], "sets": [{"ids": [2]}]}`))
+	assert.NotNil(t, err)
+	_, _, err = LoadDictionary(bytes.NewBufferString(`{"words": ["a", "A", "b"], "sets": []}`))
+	assert.NotNil(t, err)
+
+	loaded, sets, err = LoadDictionary(bytes.NewBufferString(`{"words": ["a", "B", "c"], "sets": [{"ids": [1]}]}`))
+	assert.Nil(t, err)
+	assert.True(t, sets[0].WordSet().Equal(newSet("b")))
+	assert.True(t, sets[0].Contains("B"))
+	id, _ := loaded.Lookup("c")
+	assert.Equal(t, uint32(2), id)
+
+	_, _, err = LoadDictionary(bytes.NewBufferString("not json"))
+	assert.NotNil(t, err)
+}
+
+func TestDictionaryConcurrentIntern(t *testing.T) {
+	dict := NewDictionary()
+
+	var wg sync.WaitGroup
+	sets := make([]*InternedWordSet, 8)
+	for g := range sets {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			ws := NewWordSet()
+			for i := 0; i < 500; i++ {
+				ws.Add("w" + strconv.Itoa((i*(g+1))%700))
+			}
+			sets[g] = dict.InternWordSet(ws)
+		}(g)
+	}
+	wg.Wait()
+
+	// every word got exactly one id
+	seen := map[string]bool{}
+	for i := 0; i < dict.Len(); i++ {
+		word, _ := dict.Word(uint32(i))
+		assert.False(t, seen[word], word)
+		seen[word] = true
+
+		id, _ := dict.Lookup(word)
+		assert.Equal(t, uint32(i), id)
+	}
+	assert.Equal(t, 500, sets[0].Len())
+	assert.True(t, sets[0].Contains("w499"))
+}
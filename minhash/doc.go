@@ -0,0 +1,90 @@
+package minhash
+
+import (
+	"sort"
+	"strconv"
+)
+
+// DocID identifies the document a WordSet or Signature was
+// built from, so results can be traced back to it without
+// wrapping the types. Numeric ids are stored in decimal,
+// see Int64ID and DocID.Int64.
+type DocID string
+
+// Int64ID makes a DocID out of a numeric id
+func Int64ID(id int64) DocID {
+	return DocID(strconv.FormatInt(id, 10))
+}
+
+// Int64 returns the numeric id a DocID was made from
+func (this DocID) Int64() (int64, error) {
+	return strconv.ParseInt(string(this), 10, 64)
+}
+
+// Pair is the similarity of two identified documents
+type Pair struct {
+	Left       DocID
+	Right      DocID
+	Similarity float64
+}
+
+// NewSignature signs a document with the DefaultShingler
+func NewSignature(id DocID, text string) Signature {
+	return NewSignatureWith(id, text, DefaultShingler)
+}
+
+// NewSignatureWith signs a document with the given Shingler
+func NewSignatureWith(id DocID, text string, sh Shingler) Signature {
+	return Signature{ID: id, MinHash: GenerateMinHashWith(text, sh)}
+}
+
+// CompareSignatures estimates the Jaccard index
+// of two documents from their signatures
+func CompareSignatures(left, right Signature) Pair {
+	return Pair{Left: left.ID, Right: right.ID, Similarity: minHashSimilarity(left.MinHash, right.MinHash)}
+}
+
+// CompareWordSets computes the Jaccard index of two documents
+func CompareWordSets(left, right *WordSet) Pair {
+	return Pair{Left: left.ID(), Right: right.ID(), Similarity: JaccardDistance(left, right)}
+}
+
+// RankSignatures compares a query with every candidate
+// and returns the pairs, most similar first
+func RankSignatures(query Signature, candidates []Signature) []Pair {
+	pairs := make([]Pair, 0, len(candidates))
+
+	for _, c := range candidates {
+		pairs = append(pairs, CompareSignatures(query, c))
+	}
+	sortPairs(pairs)
+
+	return pairs
+}
+
+// RankWordSets compares a query with every candidate
+// and returns the pairs, most similar first
+func RankWordSets(query *WordSet, candidates []*WordSet) []Pair {
+	pairs := make([]Pair, 0, len(candidates))
+
+	for _, c := range candidates {
+		pairs = append(pairs, CompareWordSets(query, c))
+	}
+	sortPairs(pairs)
+
+	return pairs
+}
+
+// sortPairs sorts by similarity, most similar first,
+// and by id so the order is deterministic
+func sortPairs(pairs []Pair) {
+	sort.SliceStable(pairs, func(i, j int) bool {
+		if pairs[i].Similarity != pairs[j].Similarity {
+			return pairs[i].Similarity > pairs[j].Similarity
+		}
+		if pairs[i].Left != pairs[j].Left {
+			return pairs[i].Left < pairs[j].Left
+		}
+		return pairs[i].Right < pairs[j].Right
+	})
+}
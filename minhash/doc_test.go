@@ -0,0 +1,65 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDocID(t *testing.T) {
+	id := Int64ID(42)
+	assert.Equal(t, DocID("42"), id)
+
+	n, err := id.Int64()
+	assert.Nil(t, err)
+	assert.Equal(t, int64(42), n)
+
+	_, err = DocID("job-42").Int64()
+	assert.NotNil(t, err)
+}
+
+func TestDocumentIdentity(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume"
+
+	ws := NewWordSetFromDoc("job-1", s)
+	assert.Equal(t, DocID("job-1"), ws.ID())
+	assert.True(t, ws.Equal(NewWordSetFromText(s)))
+	assert.Equal(t, DocID("job-1"), ws.Clone().ID())
+
+	ws.SetID(Int64ID(7))
+	assert.Equal(t, DocID("7"), ws.ID())
+
+	sig := NewSignature("job-1", s)
+	assert.Equal(t, DocID("job-1"), sig.ID)
+	assert.Equal(t, GenerateMinHash(s), sig.MinHash)
+}
+
+func TestRank(t *testing.T) {
+	query := "Excellent job opportunity! need Node.js, MYSQL and resume"
+	docs := map[DocID]string{
+		"same":    query,
+		"close":   "Excellent job opportunity! need Node.js, MYSQL and a resume",
+		"far":     "Part time barista wanted for a coffee bar downtown",
+		"twin":    query,
+		"partial": "Excellent job opportunity! need ASP.NET, MySQL and good skills",
+	}
+
+	sets := []*WordSet{}
+	sigs := []Signature{}
+	for id, d := range docs {
+		sets = append(sets, NewWordSetFromDoc(id, d))
+		sigs = append(sigs, NewSignature(id, d))
+	}
+
+	pairs := RankWordSets(NewWordSetFromDoc("query", query), sets)
+	assert.Equal(t, 5, len(pairs))
+	assert.Equal(t, Pair{"query", "same", 1.0}, pairs[0])
+	assert.Equal(t, Pair{"query", "twin", 1.0}, pairs[1])
+	assert.Equal(t, DocID("close"), pairs[2].Right)
+	assert.Equal(t, Pair{"query", "far", 0.0}, pairs[4])
+
+	ranked := RankSignatures(NewSignature("query", query), sigs)
+	assert.Equal(t, Pair{"query", "same", 1.0}, ranked[0])
+	assert.Equal(t, Pair{"query", "twin", 1.0}, ranked[1])
+	assert.Equal(t, DocID("close"), ranked[2].Right)
+	assert.Equal(t, 0.0, ranked[4].Similarity)
+}
@@ -0,0 +1,144 @@
+package minhash
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Explanation shows why two documents were found similar,
+// for reviewers and audit trails.
+//
+// Shared, LeftOnly and RightOnly hold the shingles as they
+// appear in the original documents, in document order.
+// Slots tells for every slot of the MinHash signatures
+// whether the two documents agree on it. Threshold is
+// the threshold the similarities were held against.
+type Explanation struct {
+	Shared    []string
+	LeftOnly  []string
+	RightOnly []string
+
+	Jaccard   float64
+	MinHash   float64
+	Slots     []bool
+	Threshold float64
+}
+
+// Explain explains the similarity of two documents
+// shingled with the DefaultShingler
+func Explain(left, right string) *Explanation {
+	return ExplainWith(left, right, DefaultShingler)
+}
+
+// ExplainWith explains the similarity of two documents
+// shingled with the given Shingler. Documents too short to
+// have a shingle have nothing in common: both similarities
+// are 0 and no slot agrees, although the signatures of two
+// empty documents are equal.
+func ExplainWith(left, right string, sh Shingler) *Explanation {
+	var e Explanation
+
+	l := NewWordSetFromTextWith(left, sh)
+	r := NewWordSetFromTextWith(right, sh)
+
+	e.Shared, e.LeftOnly = splitShingles(left, sh, r)
+	_, e.RightOnly = splitShingles(right, sh, l)
+
+	lmh := GenerateMinHashWith(left, sh)
+	rmh := GenerateMinHashWith(right, sh)
+
+	e.Jaccard = Jaccard.Similarity(l, r)
+	e.MinHash = minHashSimilarity(lmh, rmh)
+	e.Slots = SlotAgreement(lmh, rmh)
+	e.Threshold = SimilarityThreshold
+
+	if l.Len() == 0 || r.Len() == 0 {
+		e.MinHash = 0
+		e.Slots = make([]bool, len(lmh))
+	}
+
+	return &e
+}
+
+// splitShingles splits the shingles of text into the ones in
+// other and the ones that aren't, each reported once using the
+// original text of its first occurrence if the Shingler knows it
+func splitShingles(text string, sh Shingler, other *WordSet) ([]string, []string) {
+	var spans []Span
+	if ssh, ok := sh.(SpanShingler); ok {
+		spans = ssh.Spans(text)
+	} else {
+		for _, s := range sh.Shingles(text) {
+			spans = append(spans, Span{Shingle: s, Start: -1})
+		}
+	}
+
+	in := []string{}
+	out := []string{}
+	seen := map[string]bool{}
+
+	for _, span := range spans {
+		if seen[span.Shingle] {
+			continue
+		}
+		seen[span.Shingle] = true
+
+		original := span.Shingle
+		if span.Start >= 0 {
+			original = text[span.Start:span.End]
+		}
+
+		if other.Contains(span.Shingle) {
+			in = append(in, original)
+		} else {
+			out = append(out, original)
+		}
+	}
+
+	return in, out
+}
+
+// SlotAgreement tells for every slot of two
+// signatures whether they hold the same value
+func SlotAgreement(m1, m2 MinHash) []bool {
+	slots := make([]bool, len(m1))
+
+	for i := range m1 {
+		slots[i] = i < len(m2) && m1[i] == m2[i]
+	}
+	return slots
+}
+
+// Report formats the explanation for humans
+func (this *Explanation) Report() string {
+	var b strings.Builder
+
+	agree := 0
+	slots := ""
+	for _, ok := range this.Slots {
+		if ok {
+			agree += 1
+			slots += "+"
+		} else {
+			slots += "."
+		}
+	}
+
+	fmt.Fprintf(&b, "Jaccard similarity: %.3f (threshold %.3f)\n", this.Jaccard, this.Threshold)
+	fmt.Fprintf(&b, "MinHash similarity: %.3f (%d/%d slots agree)\n", this.MinHash, agree, len(this.Slots))
+	fmt.Fprintf(&b, "Slots: %s\n", slots)
+
+	writeShingles(&b, "Shared", this.Shared)
+	writeShingles(&b, "Only in left", this.LeftOnly)
+	writeShingles(&b, "Only in right", this.RightOnly)
+
+	return b.String()
+}
+
+func writeShingles(b *strings.Builder, title string, shingles []string) {
+	fmt.Fprintf(b, "%s (%d):\n", title, len(shingles))
+
+	for _, s := range shingles {
+		fmt.Fprintf(b, "  %q\n", s)
+	}
+}
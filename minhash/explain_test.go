@@ -0,0 +1,82 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestExplain(t *testing.T) {
+	left := "Excellent job opportunity! need Node.js, MYSQL and resume"
+	right := "EXCELLENT job opportunity! need ASP.NET, MySQL and resume"
+
+	e := Explain(left, right)
+
+	// shared shingles are reported as written in left
+	assert.Equal(t, []string{"Excellent job opportunity!", "job opportunity! need", "MYSQL and resume"}, e.Shared)
+	assert.Equal(t, []string{"opportunity! need Node.js,", "need Node.js, MYSQL", "Node.js, MYSQL and"}, e.LeftOnly)
+	assert.Equal(t, []string{"opportunity! need ASP.NET,", "need ASP.NET, MySQL", "ASP.NET, MySQL and"}, e.RightOnly)
+
+	assert.Equal(t, JaccardSimilarity(left, right), e.Jaccard)
+	assert.Equal(t, numHashes, len(e.Slots))
+	assert.Equal(t, stringSimilarity(left, right), e.MinHash)
+
+	e = Explain(left, left)
+	assert.Equal(t, 1.0, e.MinHash)
+	assert.Equal(t, 0, len(e.LeftOnly))
+	assert.Equal(t, 0, len(e.RightOnly))
+}
+
+func TestExplainWithoutSpans(t *testing.T) {
+	sh := NewNormalizedShingler(NewEntityMasker(AllEntities), DefaultShingler)
+	e := ExplainWith("Pay is $18 an hour", "Pay is $21 an hour", sh)
+
+	assert.Equal(t, []string{"pay is $<num>", "is $<num> an", "$<num> an hour"}, e.Shared)
+	assert.Equal(t, 1.0, e.Jaccard)
+}
+
+func TestSlotAgreement(t *testing.T) {
+	assert.Equal(t, []bool{true, false, true}, SlotAgreement(MinHash{1, 2, 3}, MinHash{1, 5, 3}))
+	assert.Equal(t, []bool{true, false}, SlotAgreement(MinHash{1, 2}, MinHash{1}))
+}
+
+func TestExplanationReport(t *testing.T) {
+	e := &Explanation{
+		Shared:    []string{"excellent job opportunity!"},
+		LeftOnly:  []string{"need Node.js"},
+		RightOnly: []string{},
+		Jaccard:   0.5,
+		MinHash:   0.5,
+		Slots:     []bool{true, false, true, false},
+		Threshold: SimilarityThreshold,
+	}
+
+	report := e.Report()
+	assert.True(t, strings.Contains(report, "Jaccard similarity: 0.500 (threshold 0.799)"))
+	assert.True(t, strings.Contains(report, "MinHash similarity: 0.500 (2/4 slots agree)"))
+	assert.True(t, strings.Contains(report, "Slots: +.+.\n"))
+	assert.True(t, strings.Contains(report, "Shared (1):\n  \"excellent job opportunity!\"\n"))
+	assert.True(t, strings.Contains(report, "Only in right (0):\n"))
+}
+
+func TestExplainShortDocuments(t *testing.T) {
+	for _, c := range [][2]string{
+		{"Senior engineer", "Barista wanted"},
+		{"", ""},
+		{"Senior engineer", "Excellent job opportunity! need Node.js"},
+	} {
+		e := Explain(c[0], c[1])
+		assert.Equal(t, 0.0, e.Jaccard, c)
+		assert.Equal(t, 0.0, e.MinHash, c)
+		assert.Equal(t, numHashes, len(e.Slots))
+
+		report := e.Report()
+		assert.True(t, strings.Contains(report, "Jaccard similarity: 0.000 (threshold 0.799)\n"), report)
+		assert.True(t, strings.Contains(report, "MinHash similarity: 0.000 (0/20 slots agree)\n"), report)
+		assert.True(t, strings.Contains(report, "Shared (0):\n"), report)
+		assert.False(t, strings.Contains(report, "NaN"), report)
+	}
+
+	e := Explain("Senior engineer", "Barista wanted")
+	assert.True(t, strings.Contains(e.Report(), "Only in left (0):\nOnly in right (0):\n"), e.Report())
+}
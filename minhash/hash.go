@@ -52,6 +52,15 @@ const (
 )
 
 type MinHash []int
+
+// Signature is a MinHash along with the document it
+// belongs to and what was learned about it while signing
+type Signature struct {
+	ID       DocID
+	MinHash  MinHash
+	Language string
+}
+
 type shingle uint32
 type shingleSet map[shingle]bool
 
@@ -121,7 +130,7 @@ func hash(s string) uint32 {
 // string2Shingle converts a string into
 // a shingle which is a hashed 32bit unsigned int
 func string2Shingle(s string) shingle {
-	return shingle(hash(s))
+	return shingle(shingleHash(s))
 }
 
 // MinHash algorithm
@@ -138,41 +147,60 @@ func string2Shingle(s string) shingle {
 //
 // 3. Return the signature to the caller which is the calculated MinHash for that ShingleSet
 func calculateMinHash(ss shingleSet) MinHash {
-	signature := MinHash{}
+	mh := newMinHasher()
 
-	var minHashCode int
-	for i := 0; i < numHashes; i++ {
-		// make min hash code to be more than
-		// the max possible value output by hash
-		minHashCode = nextPrime + 1
+	for shingle := range ss {
+		mh.add(shingle)
+	}
+
+	return mh.signature
+}
 
-		for shingle := range ss {
-			hashCode := (coeffA[i]*int(shingle) + coeffB[i]) % nextPrime
+// minHasher keeps the running minimum hash code
+// of every hash function so that a signature can be
+// built one shingle at a time without holding the
+// whole shingle set in memory
+type minHasher struct {
+	signature MinHash
+}
 
-			if hashCode < minHashCode {
-				minHashCode = hashCode
-			}
-		}
+func newMinHasher() *minHasher {
+	var mh minHasher
+	mh.signature = make(MinHash, numHashes)
 
-		signature = append(signature, minHashCode)
+	// make min hash code to be more than
+	// the max possible value output by hash
+	for i := range mh.signature {
+		mh.signature[i] = nextPrime + 1
 	}
 
-	return signature
+	return &mh
+}
+
+func (this *minHasher) add(s shingle) {
+	for i := 0; i < numHashes; i++ {
+		hashCode := (coeffA[i]*int(s) + coeffB[i]) % nextPrime
+
+		if hashCode < this.signature[i] {
+			this.signature[i] = hashCode
+		}
+	}
 }
 
 // doc2ShingleSet generates a shingle set from a
-// string document. Each shingle contain three
-// tokens from the document.  We scan per every 3 words
-// in the order they appear in the document.
-//  Each one of these triples is a shingle
+// string document using the DefaultShingler.
+// Each shingle contain three tokens from the document.
 func doc2ShingleSet(d string) shingleSet {
-	shingles := shingleSet{}
+	return doc2ShingleSetWith(d, DefaultShingler)
+}
 
-	tokens := strings.Split(d, " ")
-	for i := 0; i < len(tokens)-2; i++ {
-		words := strings.ToLower(tokens[i] + " " + tokens[i+1] + " " + tokens[i+2])
+// doc2ShingleSetWith generates a shingle set from a
+// string document with the given Shingler
+func doc2ShingleSetWith(d string, sh Shingler) shingleSet {
+	shingles := shingleSet{}
 
-		shingles[string2Shingle(words)] = true
+	for _, h := range sh.Hashes(d) {
+		shingles[shingle(h)] = true
 	}
 
 	return shingles
@@ -205,30 +233,33 @@ func MinHashFromStr(mh string) (MinHash, error) {
 }
 
 // Compute number of signature matches / num hashes
-// to get approx. Jaccard distance
+// to get approx. Jaccard distance. Signatures of
+// different lengths have nothing in common.
 func minHashSimilarity(m1, m2 MinHash) float64 {
-	matches := 0.0
+	if len(m1) != len(m2) {
+		return 0
+	}
 
+	matches := 0.0
 	for i := range m1 {
 		if m1[i] == m2[i] {
 			matches += 1
 		}
 	}
 
-	return matches / float64(numHashes)
+	return ratio(matches, float64(len(m1)))
 }
 
 // MinHashSimilar takes two min hash strings as input
 // and returns whether they are similar.
+// Strings that don't parse aren't similar.
 func MinHashSimilar(left, right string) bool {
-	l, _ := MinHashFromStr(left)
-	r, _ := MinHashFromStr(right)
-	return minHashSimilarity(l, r) > SimilarityThreshold
+	return minHashComparator.MinHashSimilar(left, right)
 }
 
 // compare two strings to see if they are similar
 func StringsSimilar(left, right string) bool {
-	return minHashSimilarity(GenerateMinHash(left), GenerateMinHash(right)) > SimilarityThreshold
+	return minHashComparator.StringsSimilar(left, right)
 }
 
 // GenerateMinHash generates a minhash from a document string
@@ -238,3 +269,9 @@ func GenerateMinHash(d string) MinHash {
 
 	return calculateMinHash(ss)
 }
+
+// GenerateMinHashWith generates a minhash from a document
+// string, shingling it with the given Shingler
+func GenerateMinHashWith(d string, sh Shingler) MinHash {
+	return calculateMinHash(doc2ShingleSetWith(d, sh))
+}
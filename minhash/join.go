@@ -0,0 +1,176 @@
+package minhash
+
+import (
+	"math"
+	"sort"
+)
+
+/*
+ ------------------ Similarity join ----------------------
+ SimilarityJoin finds every pair of WordSets whose Jaccard index
+ is at least a threshold t without comparing every pair, following
+ AllPairs (Bayardo et al.) and PPJoin (Xiao et al.).
+
+ Words are ordered from rarest to most common and every set is
+ sorted in that order. Two sets with J >= t must share a word in
+ the first |x| - ceil(t|x|) + 1 words of each (the prefix), so only
+ sets sharing a prefix word become candidates:
+
+ - length filter: a set y can only reach t with x if |y| >= t|x|
+ - positional filter: the overlap still possible after the
+   matching prefix positions has to reach ceil(t/(1+t) (|x|+|y|))
+
+ Candidates are verified with the exact overlap. The result is
+ exact, it's the ground truth to measure MinHash recall against.
+*/
+
+// allowance for float error when rounding thresholds
+const joinEpsilon = 1e-9
+
+// SimilarityJoin returns every pair of sets with a Jaccard index
+// of at least threshold, most similar first. Pairs are reported
+// once, by the IDs of the sets.
+func SimilarityJoin(sets []*WordSet, threshold float64) []Pair {
+	if threshold <= 0 {
+		return joinAll(sets)
+	}
+
+	records := joinRecords(sets)
+
+	// smaller sets first, so every set
+	// only probes sets no larger than itself
+	sort.SliceStable(records, func(i, j int) bool {
+		return len(records[i].tokens) < len(records[j].tokens)
+	})
+
+	type posting struct {
+		record int
+		pos    int
+	}
+	index := map[int][]posting{}
+	pairs := []Pair{}
+
+	for xi, x := range records {
+		size := len(x.tokens)
+		if size == 0 {
+			continue
+		}
+
+		minSize := int(math.Ceil(threshold*float64(size) - joinEpsilon))
+		prefix := size - minSize + 1
+
+		// overlap found so far per candidate, -1 once pruned
+		overlap := map[int]int{}
+
+		for i := 0; i < prefix; i++ {
+			for _, p := range index[x.tokens[i]] {
+				y := records[p.record]
+				if len(y.tokens) < minSize || overlap[p.record] < 0 {
+					continue
+				}
+
+				required := int(math.Ceil(threshold/(1+threshold)*float64(size+len(y.tokens)) - joinEpsilon))
+				bound := overlap[p.record] + 1 + minInt(size-i-1, len(y.tokens)-p.pos-1)
+
+				if bound < required {
+					overlap[p.record] = -1
+				} else {
+					overlap[p.record] += 1
+				}
+			}
+			index[x.tokens[i]] = append(index[x.tokens[i]], posting{xi, i})
+		}
+
+		for yi, o := range overlap {
+			if o <= 0 {
+				continue
+			}
+
+			y := records[yi]
+			intersection := mergeOverlap(x.tokens, y.tokens)
+			similarity := float64(intersection) / float64(size+len(y.tokens)-intersection)
+
+			if similarity >= threshold-joinEpsilon {
+				pairs = append(pairs, orderedPair(y.id, x.id, similarity))
+			}
+		}
+	}
+
+	sortPairs(pairs)
+	return pairs
+}
+
+type joinRecord struct {
+	id     DocID
+	tokens []int
+}
+
+// joinRecords maps every word to its rank in the global
+// order, rarest first, and sorts every set by rank
+func joinRecords(sets []*WordSet) []joinRecord {
+	df := map[string]int{}
+	for _, ws := range sets {
+		for word := range ws.membership {
+			df[word] += 1
+		}
+	}
+
+	words := make([]string, 0, len(df))
+	for word := range df {
+		words = append(words, word)
+	}
+	sort.Slice(words, func(i, j int) bool {
+		if df[words[i]] != df[words[j]] {
+			return df[words[i]] < df[words[j]]
+		}
+		return words[i] < words[j]
+	})
+
+	rank := map[string]int{}
+	for i, word := range words {
+		rank[word] = i
+	}
+
+	records := make([]joinRecord, 0, len(sets))
+	for _, ws := range sets {
+		tokens := make([]int, 0, ws.Len())
+		for word := range ws.membership {
+			tokens = append(tokens, rank[word])
+		}
+		sort.Ints(tokens)
+
+		records = append(records, joinRecord{id: ws.ID(), tokens: tokens})
+	}
+	return records
+}
+
+// joinAll compares every pair, every pair passes a threshold of 0
+func joinAll(sets []*WordSet) []Pair {
+	pairs := []Pair{}
+
+	for i := range sets {
+		for j := i + 1; j < len(sets); j++ {
+			p := CompareWordSets(sets[i], sets[j])
+			pairs = append(pairs, orderedPair(p.Left, p.Right, p.Similarity))
+		}
+	}
+
+	sortPairs(pairs)
+	return pairs
+}
+
+// orderedPair puts the smaller id on the left
+// so every pair is reported the same way
+func orderedPair(a, b DocID, similarity float64) Pair {
+	if b < a {
+		a, b = b, a
+	}
+	return Pair{Left: a, Right: b, Similarity: similarity}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
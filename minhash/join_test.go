@@ -0,0 +1,90 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+// bruteForceJoin compares every pair
+func bruteForceJoin(sets []*WordSet, threshold float64) []Pair {
+	pairs := []Pair{}
+
+	for _, p := range joinAll(sets) {
+		if p.Similarity >= threshold {
+			pairs = append(pairs, p)
+		}
+	}
+	return pairs
+}
+
+func TestSimilarityJoin(t *testing.T) {
+	docs := map[DocID]string{
+		"a": "Excellent job opportunity! need Node.js, MYSQL and resume",
+		"b": "Excellent job opportunity! need Node.js, MYSQL and a resume",
+		"c": "Excellent job opportunity! need ASP.NET, MySQL and good skills in microsoft office",
+		"d": "Excellent job opportunity! need Node.js, MYSQL and resume",
+		"e": "Part time barista wanted for a coffee bar downtown",
+		"f": "",
+	}
+
+	sets := []*WordSet{}
+	for id, d := range docs {
+		sets = append(sets, NewWordSetFromDoc(id, d))
+	}
+
+	pairs := SimilarityJoin(sets, 0.5)
+	assert.Equal(t, []Pair{
+		{"a", "d", 1.0},
+		{"a", "b", JaccardSimilarity(docs["a"], docs["b"])},
+		{"b", "d", JaccardSimilarity(docs["a"], docs["b"])},
+	}, pairs)
+
+	assert.Equal(t, []Pair{{"a", "d", 1.0}}, SimilarityJoin(sets, 1.0))
+	assert.Equal(t, 15, len(SimilarityJoin(sets, 0)))
+	assert.Equal(t, 0, len(SimilarityJoin(nil, 0.5)))
+}
+
+func TestSimilarityJoinMatchesBruteForce(t *testing.T) {
+	r := rand.New(rand.NewSource(42))
+
+	// sets drawn from a skewed vocabulary so that
+	// plenty of pairs land around every threshold
+	sets := []*WordSet{}
+	for i := 0; i < 150; i++ {
+		ws := NewWordSet()
+		ws.SetID(Int64ID(int64(i)))
+
+		size := 1 + r.Intn(12)
+		for ws.Len() < size {
+			ws.Add("w" + strconv.Itoa(int(r.ExpFloat64()*4)))
+		}
+		sets = append(sets, ws)
+	}
+
+	for _, threshold := range []float64{0.1, 0.3, 0.5, 0.6, 2.0 / 3.0, 0.8, 0.9, 1.0} {
+		expected := bruteForceJoin(sets, threshold)
+		assert.Equal(t, expected, SimilarityJoin(sets, threshold), threshold)
+		assert.True(t, threshold > 0.9 || len(expected) > 0, threshold)
+	}
+}
+
+func BenchmarkSimilarityJoin(b *testing.B) {
+	r := rand.New(rand.NewSource(42))
+
+	sets := []*WordSet{}
+	for i := 0; i < 2000; i++ {
+		ws := NewWordSet()
+		ws.SetID(Int64ID(int64(i)))
+		for j := 0; j < 30; j++ {
+			ws.Add("w" + strconv.Itoa(r.Intn(5000)))
+		}
+		sets = append(sets, ws)
+	}
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		SimilarityJoin(sets, 0.8)
+	}
+}
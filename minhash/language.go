@@ -0,0 +1,247 @@
+package minhash
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+/*
+ ------------------ Language detection ----------------------
+ The right tokenizer, normalization and stopwords depend on the
+ language of a document. DetectLanguage is a small embedded language
+ identifier: documents in a script used by a single language (or
+ family) are identified by their script, and Latin script documents
+ by comparing their character trigrams with trigram profiles learned
+ from the short samples below (naive Bayes).
+
+ Languages are reported as ISO 639-1 codes.
+*/
+
+// UnknownLanguage is returned when a document
+// doesn't hold enough text to tell
+const UnknownLanguage = ""
+
+// fewest letters we need to guess a language
+const minLanguageLetters = 10
+
+// scripts that identify a language on their own.
+// Han is handled separately since it's shared by
+// Chinese and Japanese.
+var languageScripts = []struct {
+	lang   string
+	script *unicode.RangeTable
+}{
+	{"ja", unicode.Hiragana},
+	{"ja", unicode.Katakana},
+	{"ko", unicode.Hangul},
+	{"th", unicode.Thai},
+	{"ru", unicode.Cyrillic},
+	{"el", unicode.Greek},
+	{"ar", unicode.Arabic},
+	{"he", unicode.Hebrew},
+	{"hi", unicode.Devanagari},
+}
+
+// samples the Latin script trigram profiles are learned from
+var languageSamples = map[string]string{
+	"en": `All human beings are born free and equal in dignity and rights. They are endowed with reason and
+		conscience and should act towards one another in a spirit of brotherhood. We are looking for an experienced
+		developer to join our team. The candidate will work with the customers and have the opportunity to grow
+		with the company. Please send your resume and we will contact you.`,
+	"es": `Todos los seres humanos nacen libres e iguales en dignidad y derechos y, dotados como están de razón y
+		conciencia, deben comportarse fraternalmente los unos con los otros. Buscamos un desarrollador con experiencia
+		para unirse a nuestro equipo. El candidato trabajará con los clientes y tendrá la oportunidad de crecer con la
+		empresa. Por favor envíe su currículum y nos pondremos en contacto con usted.`,
+	"fr": `Tous les êtres humains naissent libres et égaux en dignité et en droits. Ils sont doués de raison et de
+		conscience et doivent agir les uns envers les autres dans un esprit de fraternité. Nous recherchons un
+		développeur expérimenté pour rejoindre notre équipe. Le candidat travaillera avec les clients et aura la
+		possibilité d'évoluer avec l'entreprise. Merci d'envoyer votre curriculum vitae et nous vous contacterons.`,
+	"de": `Alle Menschen sind frei und gleich an Würde und Rechten geboren. Sie sind mit Vernunft und Gewissen begabt
+		und sollen einander im Geist der Brüderlichkeit begegnen. Wir suchen einen erfahrenen Entwickler zur
+		Verstärkung unseres Teams. Der Kandidat wird mit den Kunden arbeiten und hat die Möglichkeit, mit dem
+		Unternehmen zu wachsen. Bitte senden Sie uns Ihren Lebenslauf und wir werden uns bei Ihnen melden.`,
+	"it": `Tutti gli esseri umani nascono liberi ed eguali in dignità e diritti. Essi sono dotati di ragione e di
+		coscienza e devono agire gli uni verso gli altri in spirito di fratellanza. Cerchiamo uno sviluppatore con
+		esperienza per entrare a far parte del nostro gruppo. Il candidato lavorerà con i clienti e avrà la
+		possibilità di crescere con l'azienda. Si prega di inviare il proprio curriculum e vi contatteremo.`,
+	"pt": `Todos os seres humanos nascem livres e iguais em dignidade e em direitos. Dotados de razão e de
+		consciência, devem agir uns para com os outros em espírito de fraternidade. Procuramos um desenvolvedor com
+		experiência para fazer parte da nossa equipe. O candidato trabalhará com os clientes e terá a oportunidade de
+		crescer com a empresa. Por favor envie o seu currículo e entraremos em contato com você.`,
+	"nl": `Alle mensen worden vrij en gelijk in waardigheid en rechten geboren. Zij zijn begiftigd met verstand en
+		geweten, en behoren zich jegens elkander in een geest van broederschap te gedragen. Wij zoeken een ervaren
+		ontwikkelaar om ons team te versterken. De kandidaat zal met de klanten werken en krijgt de kans om met het
+		bedrijf mee te groeien. Stuur ons uw cv en wij nemen contact met u op.`,
+}
+
+// trigram smoothing, roughly the number of
+// trigrams a language uses
+const trigramVocabulary = 10000
+
+type trigramProfile struct {
+	counts map[string]int
+	total  int
+}
+
+var languageProfiles = map[string]*trigramProfile{}
+
+func init() {
+	for lang, sample := range languageSamples {
+		profile := trigramProfile{counts: map[string]int{}}
+
+		for _, t := range trigrams(sample) {
+			profile.counts[t] += 1
+			profile.total += 1
+		}
+		languageProfiles[lang] = &profile
+	}
+}
+
+// trigrams returns the character trigrams of the words
+// in the text, padded with a space on either side
+func trigrams(text string) []string {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+
+	grams := []string{}
+	for _, w := range words {
+		runes := []rune(" " + w + " ")
+
+		for i := 0; i+3 <= len(runes); i++ {
+			grams = append(grams, string(runes[i:i+3]))
+		}
+	}
+	return grams
+}
+
+// DetectLanguage guesses the language of a document
+// and returns its ISO 639-1 code, or UnknownLanguage.
+//
+// The coverage is limited. A script is taken to be a single
+// language, so all Cyrillic text is reported as "ru" (Ukrainian,
+// Bulgarian, Serbian, ...), all Arabic script text as "ar" (Persian,
+// Urdu, ...) and all Devanagari as "hi". Latin script text is only
+// told apart between en, es, fr, de, it, pt and nl, each learned
+// from a single paragraph, so short texts and other Latin script
+// languages get the closest of those. When scripts are tied the
+// language code that sorts first wins.
+func DetectLanguage(text string) string {
+	letters := 0
+	latin := 0
+	han := 0
+	scripts := map[string]int{}
+
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		letters += 1
+
+		switch {
+		case unicode.Is(unicode.Latin, r):
+			latin += 1
+		case unicode.Is(unicode.Han, r):
+			han += 1
+		default:
+			for _, ls := range languageScripts {
+				if unicode.Is(ls.script, r) {
+					scripts[ls.lang] += 1
+					break
+				}
+			}
+		}
+	}
+
+	if letters < minLanguageLetters {
+		return UnknownLanguage
+	}
+
+	// Japanese mixes kana and kanji
+	if scripts["ja"] > 0 {
+		scripts["ja"] += han
+	} else {
+		scripts["zh"] = han
+	}
+
+	best := UnknownLanguage
+	for lang, n := range scripts {
+		if n <= latin {
+			continue
+		}
+		// map order is random, break ties by language code
+		if best == UnknownLanguage || n > scripts[best] || (n == scripts[best] && lang < best) {
+			best = lang
+		}
+	}
+	if best != UnknownLanguage || latin == 0 {
+		return best
+	}
+
+	return detectLatinLanguage(text)
+}
+
+// detectLatinLanguage picks the language whose trigram
+// profile is most likely to have produced the text
+func detectLatinLanguage(text string) string {
+	grams := trigrams(text)
+
+	best := UnknownLanguage
+	bestScore := math.Inf(-1)
+	for lang, profile := range languageProfiles {
+		score := 0.0
+
+		for _, t := range grams {
+			score += math.Log(float64(profile.counts[t]+1) / float64(profile.total+trigramVocabulary))
+		}
+
+		if score > bestScore || (score == bestScore && lang < best) {
+			best = lang
+			bestScore = score
+		}
+	}
+	return best
+}
+
+// LanguageShingler detects the language of every document
+// and shingles it with the Shingler configured for that
+// language, so every part of a multilingual corpus gets the
+// right tokenizer and normalization. Documents in languages
+// without a Shingler of their own use Default.
+type LanguageShingler struct {
+	Shinglers map[string]Shingler
+	Default   Shingler
+}
+
+func NewLanguageShingler(def Shingler) *LanguageShingler {
+	return &LanguageShingler{Shinglers: map[string]Shingler{}, Default: def}
+}
+
+// For returns the Shingler used for a language
+func (this *LanguageShingler) For(lang string) Shingler {
+	if sh, ok := this.Shinglers[lang]; ok {
+		return sh
+	}
+	return this.Default
+}
+
+func (this *LanguageShingler) Shingles(text string) []string {
+	return this.For(DetectLanguage(text)).Shingles(text)
+}
+
+func (this *LanguageShingler) Hashes(text string) []uint32 {
+	return this.For(DetectLanguage(text)).Hashes(text)
+}
+
+// Sign detects the language of a document once, signs it
+// with the Shingler for that language and reports both
+func (this *LanguageShingler) Sign(id DocID, text string) Signature {
+	lang := DetectLanguage(text)
+
+	return Signature{
+		ID:       id,
+		MinHash:  GenerateMinHashWith(text, this.For(lang)),
+		Language: lang,
+	}
+}
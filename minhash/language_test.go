@@ -0,0 +1,59 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	docs := map[string]string{
+		"en": "Senior software engineer wanted for a growing startup, competitive salary and benefits",
+		"es": "Se busca ingeniero de software con experiencia en desarrollo de aplicaciones web para nuestra oficina",
+		"fr": "Nous cherchons une vendeuse pour notre boutique du centre ville, poste à temps plein",
+		"de": "Wir bieten Ihnen eine unbefristete Stelle in einem dynamischen Team mit flexiblen Arbeitszeiten",
+		"it": "Cerchiamo un cameriere per il nostro ristorante nel centro della città, anche senza esperienza",
+		"pt": "Estamos contratando motoristas para entregas na região, com salário e benefícios",
+		"nl": "Wij zoeken een enthousiaste medewerker voor onze winkel in het centrum van de stad",
+		"zh": "招聘高级软件工程师，负责后端开发",
+		"ja": "ソフトウェアエンジニアを募集しています。経験者優遇",
+		"ko": "소프트웨어 엔지니어를 모집합니다",
+		"th": "รับสมัครพนักงานขายประจำร้าน",
+		"ru": "Требуется инженер-программист в нашу команду",
+	}
+
+	for lang, d := range docs {
+		assert.Equal(t, lang, DetectLanguage(d), d)
+	}
+
+	assert.Equal(t, UnknownLanguage, DetectLanguage("12345 !!"))
+	assert.Equal(t, UnknownLanguage, DetectLanguage("Go"))
+
+	// tied scripts are broken by language code, every time
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, "el", DetectLanguage("αβγδε אבגדה"))
+	}
+
+	// a script stands for a single language
+	assert.Equal(t, "ru", DetectLanguage("Потрібен програміст до нашої команди"))
+}
+
+func TestLanguageShingler(t *testing.T) {
+	sh := NewLanguageShingler(DefaultShingler)
+	sh.Shinglers["zh"] = NewWordShingler(2)
+
+	zh := "招聘高级软件工程师，负责后端开发"
+	en := "Senior software engineer wanted for a growing startup"
+
+	assert.Equal(t, NewWordShingler(2).Shingles(zh), sh.Shingles(zh))
+	assert.Equal(t, DefaultShingler.Shingles(en), sh.Shingles(en))
+	assert.Equal(t, DefaultShingler.Hashes(en), sh.Hashes(en))
+
+	sig := sh.Sign("zh-1", zh)
+	assert.Equal(t, DocID("zh-1"), sig.ID)
+	assert.Equal(t, "zh", sig.Language)
+	assert.Equal(t, GenerateMinHashWith(zh, NewWordShingler(2)), sig.MinHash)
+
+	sig = sh.Sign("en-1", en)
+	assert.Equal(t, "en", sig.Language)
+	assert.Equal(t, GenerateMinHash(en), sig.MinHash)
+}
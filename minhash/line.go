@@ -0,0 +1,134 @@
+package minhash
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	whitespaceRun = regexp.MustCompile(`[ \t\f\v]+`)
+	digitRun      = regexp.MustCompile(`[0-9]+`)
+)
+
+// LineShingler builds shingles out of K consecutive lines
+// instead of words. This is the natural unit for log files,
+// configuration files and CSV exports. With K = 1 every line
+// is a shingle of its own.
+//
+// Each line can be normalized before shingling:
+// CollapseWhitespace trims the line and turns runs of blanks
+// into a single space, MaskNumbers replaces every run of
+// digits with "#" and SkipBlank drops empty lines.
+type LineShingler struct {
+	K                  int
+	CollapseWhitespace bool
+	MaskNumbers        bool
+	SkipBlank          bool
+}
+
+// NewLineShingler returns a LineShingler that ignores
+// indentation, spacing and blank lines.
+// It panics if k is less than 1.
+func NewLineShingler(k int) *LineShingler {
+	if k < 1 {
+		panic("minhash: LineShingler needs K >= 1, got " + strconv.Itoa(k))
+	}
+	return &LineShingler{
+		K:                  k,
+		CollapseWhitespace: true,
+		SkipBlank:          true,
+	}
+}
+
+func (this *LineShingler) Shingles(text string) []string {
+	shingles := []string{}
+
+	// reading from a string never fails
+	this.scan(strings.NewReader(text), func(s string, start, end int) {
+		shingles = append(shingles, s)
+	})
+
+	return shingles
+}
+
+func (this *LineShingler) Hashes(text string) []uint32 {
+	return HashShingles(this.Shingles(text))
+}
+
+func (this *LineShingler) StreamHashes(r io.Reader, emit func(uint32)) error {
+	return this.scan(r, func(s string, start, end int) {
+		emit(shingleHash(s))
+	})
+}
+
+func (this *LineShingler) Spans(text string) []Span {
+	spans := []Span{}
+
+	// reading from a string never fails
+	this.scan(strings.NewReader(text), func(s string, start, end int) {
+		spans = append(spans, Span{Shingle: s, Start: start, End: end})
+	})
+
+	return spans
+}
+
+// normalize applies the configured normalization to a line
+func (this *LineShingler) normalize(line string) string {
+	if this.CollapseWhitespace {
+		line = strings.TrimSpace(whitespaceRun.ReplaceAllString(line, " "))
+	}
+	if this.MaskNumbers {
+		line = digitRun.ReplaceAllString(line, "#")
+	}
+	return line
+}
+
+// scan slides a window of K lines over the text read from r
+// and emits every window joined by newlines, along with the
+// byte offsets of the window in the text
+func (this *LineShingler) scan(r io.Reader, emit func(s string, start, end int)) error {
+	br := bufio.NewReader(r)
+	window := make([]string, 0, this.K)
+	starts := make([]int, 0, this.K)
+
+	pos := 0
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return err
+		}
+
+		eof := err == io.EOF
+		start := pos
+		pos += len(line)
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		end := start + len(line)
+		line = this.normalize(line)
+
+		// an empty read at the end (after a trailing
+		// newline, or of an empty text) is not a line
+		if !(eof && start == pos) && !(this.SkipBlank && line == "") {
+			// slide the window over by one line
+			if len(window) == this.K && this.K > 0 {
+				copy(window, window[1:])
+				copy(starts, starts[1:])
+				window = window[:this.K-1]
+				starts = starts[:this.K-1]
+			}
+			window = append(window, line)
+			starts = append(starts, start)
+
+			if len(window) == this.K {
+				emit(strings.Join(window, "\n"), starts[0], end)
+			}
+		}
+
+		if eof {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+const hostConfig = `server:
+  port: 8080
+  host:   web-01.example.com
+
+logging:
+  level: info
+`
+
+func TestLineShingles(t *testing.T) {
+	sh := NewLineShingler(1)
+	assert.Equal(t, []string{"server:", "port: 8080", "host: web-01.example.com", "logging:", "level: info"}, sh.Shingles(hostConfig))
+
+	sh = NewLineShingler(2)
+	assert.Equal(t, "server:\nport: 8080", sh.Shingles(hostConfig)[0])
+	assert.Equal(t, 4, len(sh.Shingles(hostConfig)))
+
+	sh = &LineShingler{K: 1}
+	assert.Equal(t, []string{"a ", "", "b"}, sh.Shingles("a \r\n\nb\n"))
+	assert.Equal(t, []string{"a"}, sh.Shingles("a"))
+	assert.Equal(t, []string{""}, sh.Shingles("\n"))
+
+	// empty documents have no shingles, so they don't all collide
+	assert.Equal(t, []string{}, sh.Shingles(""))
+	assert.Equal(t, []string{}, NewLineShingler(2).Shingles(""))
+
+	sh = NewLineShingler(1)
+	sh.MaskNumbers = true
+	assert.Equal(t, "host: web-#.example.com", sh.Shingles(hostConfig)[2])
+}
+
+func TestLineShinglerK(t *testing.T) {
+	assert.Panics(t, func() { NewLineShingler(0) })
+	assert.Panics(t, func() { NewLineShingler(-1) })
+	assert.Equal(t, []string{}, (&LineShingler{K: 0}).Shingles("a\nb"))
+}
+
+func TestLineSpans(t *testing.T) {
+	spans := NewLineShingler(2).Spans(hostConfig)
+
+	assert.Equal(t, "  port: 8080\n  host:   web-01.example.com", hostConfig[spans[1].Start:spans[1].End])
+	assert.Equal(t, "  level: info", hostConfig[spans[3].Start:spans[3].End][len("logging:\n"):])
+}
+
+func TestLineShinglerSimilarity(t *testing.T) {
+	drifted := strings.Replace(hostConfig, "web-01", "web-02", 1)
+	drifted = strings.Replace(drifted, "  port: 8080", "    port:  8080", 1)
+
+	sh := NewLineShingler(1)
+	assert.Equal(t, 0.6666666666666666, JaccardDistance(NewWordSetFromTextWith(hostConfig, sh), NewWordSetFromTextWith(drifted, sh)))
+
+	sh.MaskNumbers = true
+	assert.Equal(t, 1.0, JaccardDistance(NewWordSetFromTextWith(hostConfig, sh), NewWordSetFromTextWith(drifted, sh)))
+	assert.Equal(t, GenerateMinHashWith(hostConfig, sh), GenerateMinHashWith(drifted, sh))
+
+	mh, err := GenerateMinHashFromReaderWith(iotest.HalfReader(strings.NewReader(hostConfig)), sh)
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHashWith(hostConfig, sh), mh)
+}
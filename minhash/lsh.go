@@ -0,0 +1,212 @@
+package minhash
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+/*
+ ------------------ LSH index ----------------------
+ Comparing a signature with every indexed signature doesn't scale
+ to millions of documents. The banding technique of locality
+ sensitive hashing (Mining of Massive Datasets, chapter 3.4) only
+ looks at documents likely to be similar.
+
+ A MinHash is split into b bands of r rows. Every band is hashed
+ into a bucket and two documents become candidates when they land
+ in the same bucket for at least one band. Two documents with a
+ Jaccard index of s become candidates with probability
+
+     1 - (1 - s^r)^b
+
+ an S curve whose steepest point is around (1/b)^(1/r). More rows
+ make the index pickier (fewer false positives), more bands make
+ it more forgiving (fewer false negatives). See LSHParams.
+
+ Candidates still have to be checked against the threshold,
+ with MinHashSimilar or the exact WordSet measures.
+*/
+
+var errShortSignature = errors.New("minhash: signature is shorter than bands * rows")
+
+// LSHIndex buckets MinHash signatures by bands so that
+// similar documents can be found without comparing every pair.
+// It's safe for concurrent use.
+type LSHIndex struct {
+	mu      sync.RWMutex
+	bands   int
+	rows    int
+	buckets []map[uint64][]DocID
+
+	// bucket keys of every document, for Remove
+	keys map[DocID][]uint64
+}
+
+// NewLSHIndex returns an index splitting signatures into
+// bands of rows values. bands * rows can't be larger
+// than the length of the signatures, numHashes by default.
+// It panics if bands or rows is less than 1.
+func NewLSHIndex(bands, rows int) *LSHIndex {
+	if bands < 1 || rows < 1 {
+		panic(fmt.Sprintf("minhash: LSHIndex needs at least 1 band of 1 row, got %d bands of %d rows", bands, rows))
+	}
+
+	index := LSHIndex{
+		bands:   bands,
+		rows:    rows,
+		buckets: make([]map[uint64][]DocID, bands),
+		keys:    map[DocID][]uint64{},
+	}
+	for i := range index.buckets {
+		index.buckets[i] = map[uint64][]DocID{}
+	}
+
+	return &index
+}
+
+// LSHParams picks the bands and rows using all n values
+// of a signature whose S curve is steepest closest to
+// the threshold. Use it to build an index for numHashes
+// long signatures:
+//
+//	NewLSHIndex(LSHParams(SimilarityThreshold, numHashes))
+//
+// n less than 1 is treated as 1, a single band of 1 row.
+func LSHParams(threshold float64, n int) (bands, rows int) {
+	if n < 1 {
+		n = 1
+	}
+	bands, rows = n, 1
+	best := math.Inf(1)
+
+	for r := 1; r <= n; r++ {
+		if n%r != 0 {
+			continue
+		}
+
+		b := n / r
+		distance := math.Abs(math.Pow(1/float64(b), 1/float64(r)) - threshold)
+		if distance < best {
+			bands, rows, best = b, r, distance
+		}
+	}
+	return bands, rows
+}
+
+// Bands returns the number of bands and rows per band
+func (this *LSHIndex) Bands() (bands, rows int) {
+	return this.bands, this.rows
+}
+
+// bandKeys hashes every band of the signature.
+// FNV-1a over the values, collisions only
+// add a few candidates.
+func (this *LSHIndex) bandKeys(sig MinHash) ([]uint64, error) {
+	if len(sig) < this.bands*this.rows {
+		return nil, errShortSignature
+	}
+
+	keys := make([]uint64, this.bands)
+	for b := range keys {
+		h := uint64(14695981039346656037)
+
+		for _, v := range sig[b*this.rows : (b+1)*this.rows] {
+			for i := 0; i < 8; i++ {
+				h ^= uint64(v>>(8*i)) & 0xff
+				h *= 1099511628211
+			}
+		}
+		keys[b] = h
+	}
+	return keys, nil
+}
+
+// Insert adds the signature of a document to the index.
+// Inserting an id again replaces its signature.
+func (this *LSHIndex) Insert(id DocID, sig MinHash) error {
+	keys, err := this.bandKeys(sig)
+	if err != nil {
+		return err
+	}
+
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.remove(id)
+	for b, key := range keys {
+		this.buckets[b][key] = append(this.buckets[b][key], id)
+	}
+	this.keys[id] = keys
+
+	return nil
+}
+
+// Remove drops a document from the index
+func (this *LSHIndex) Remove(id DocID) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.remove(id)
+}
+
+func (this *LSHIndex) remove(id DocID) {
+	keys, ok := this.keys[id]
+	if !ok {
+		return
+	}
+
+	for b, key := range keys {
+		bucket := this.buckets[b][key]
+		for i, other := range bucket {
+			if other == id {
+				bucket = append(bucket[:i], bucket[i+1:]...)
+				break
+			}
+		}
+
+		if len(bucket) == 0 {
+			delete(this.buckets[b], key)
+		} else {
+			this.buckets[b][key] = bucket
+		}
+	}
+	delete(this.keys, id)
+}
+
+// Len returns the number of documents in the index
+func (this *LSHIndex) Len() int {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	return len(this.keys)
+}
+
+// Query returns the ids of the documents sharing a bucket
+// with the signature in at least one band, sorted
+func (this *LSHIndex) Query(sig MinHash) ([]DocID, error) {
+	keys, err := this.bandKeys(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	seen := map[DocID]struct{}{}
+	candidates := []DocID{}
+
+	for b, key := range keys {
+		for _, id := range this.buckets[b][key] {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				candidates = append(candidates, id)
+			}
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i] < candidates[j] })
+	return candidates, nil
+}
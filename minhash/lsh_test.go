@@ -0,0 +1,150 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLSHIndex(t *testing.T) {
+	index := NewLSHIndex(3, 2)
+
+	assert.Nil(t, index.Insert("a", MinHash{1, 2, 3, 4, 5, 6}))
+	assert.Nil(t, index.Insert("b", MinHash{1, 2, 9, 9, 9, 9}))
+	assert.Nil(t, index.Insert("c", MinHash{7, 7, 7, 7, 5, 6}))
+	assert.Nil(t, index.Insert("d", MinHash{2, 1, 4, 3, 6, 5}))
+	assert.Equal(t, 4, index.Len())
+
+	// a band has to match as a whole, in order
+	candidates, err := index.Query(MinHash{1, 2, 3, 4, 5, 6})
+	assert.Nil(t, err)
+	assert.Equal(t, []DocID{"a", "b", "c"}, candidates)
+
+	candidates, _ = index.Query(MinHash{0, 0, 9, 9, 0, 0})
+	assert.Equal(t, []DocID{"b"}, candidates)
+
+	candidates, _ = index.Query(MinHash{0, 0, 0, 0, 0, 0})
+	assert.Equal(t, []DocID{}, candidates)
+
+	// values past bands * rows are ignored
+	candidates, _ = index.Query(MinHash{0, 0, 0, 0, 6, 5, 1})
+	assert.Equal(t, []DocID{"d"}, candidates)
+
+	_, err = index.Query(MinHash{1, 2, 3})
+	assert.NotNil(t, err)
+	assert.NotNil(t, index.Insert("e", MinHash{1, 2, 3}))
+	assert.Equal(t, 4, index.Len())
+}
+
+func TestNewLSHIndex(t *testing.T) {
+	assert.Panics(t, func() { NewLSHIndex(-1, 2) })
+	assert.Panics(t, func() { NewLSHIndex(0, 2) })
+	assert.Panics(t, func() { NewLSHIndex(4, 0) })
+
+	bands, rows := NewLSHIndex(4, 5).Bands()
+	assert.Equal(t, 4, bands)
+	assert.Equal(t, 5, rows)
+}
+
+func TestLSHIndexReplaceAndRemove(t *testing.T) {
+	index := NewLSHIndex(2, 2)
+	index.Insert("a", MinHash{1, 2, 3, 4})
+	index.Insert("b", MinHash{1, 2, 0, 0})
+
+	// inserting again moves the document
+	index.Insert("a", MinHash{5, 6, 7, 8})
+	assert.Equal(t, 2, index.Len())
+
+	candidates, _ := index.Query(MinHash{1, 2, 3, 4})
+	assert.Equal(t, []DocID{"b"}, candidates)
+	candidates, _ = index.Query(MinHash{5, 6, 9, 9})
+	assert.Equal(t, []DocID{"a"}, candidates)
+
+	index.Remove("b")
+	index.Remove("missing")
+	assert.Equal(t, 1, index.Len())
+
+	candidates, _ = index.Query(MinHash{1, 2, 3, 4})
+	assert.Equal(t, []DocID{}, candidates)
+}
+
+func TestLSHIndexDocuments(t *testing.T) {
+	words := strings.Fields(longDocument(0))[:300]
+	doc := strings.Join(words, " ")
+
+	edited := append([]string{}, words...)
+	edited[150] = "edited"
+
+	index := NewLSHIndex(LSHParams(SimilarityThreshold, numHashes))
+	index.Insert("original", GenerateMinHash(doc))
+	index.Insert("unrelated", GenerateMinHash("Part time barista wanted for a coffee bar downtown, apply within"))
+
+	// J = 0.98, a candidate in all but about 1 in 10^6 runs
+	candidates, err := index.Query(GenerateMinHash(strings.Join(edited, " ")))
+	assert.Nil(t, err)
+	assert.Equal(t, []DocID{"original"}, candidates)
+}
+
+func TestLSHParams(t *testing.T) {
+	bands, rows := LSHParams(SimilarityThreshold, numHashes)
+	assert.Equal(t, 4, bands)
+	assert.Equal(t, 5, rows)
+
+	bands, rows = LSHParams(0.5, numHashes)
+	assert.Equal(t, 5, bands)
+	assert.Equal(t, 4, rows)
+
+	bands, rows = LSHParams(0.01, numHashes)
+	assert.Equal(t, 20, bands)
+	assert.Equal(t, 1, rows)
+
+	// too short signatures still give a usable index
+	for _, n := range []int{0, -3} {
+		bands, rows = LSHParams(SimilarityThreshold, n)
+		assert.Equal(t, 1, bands)
+		assert.Equal(t, 1, rows)
+		assert.NotPanics(t, func() { NewLSHIndex(LSHParams(SimilarityThreshold, n)) })
+	}
+}
+
+func TestLSHIndexConcurrent(t *testing.T) {
+	index := NewLSHIndex(2, 2)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 200; i++ {
+				id := Int64ID(int64(g*1000 + i))
+				index.Insert(id, MinHash{g, i % 5, i, i})
+				index.Query(MinHash{g, 0, 0, 0})
+				if i%3 == 0 {
+					index.Remove(id)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 4*133, index.Len())
+
+	candidates, _ := index.Query(MinHash{0, 0, -1, -1})
+	assert.Equal(t, 26, len(candidates))
+}
+
+func BenchmarkLSHIndexQuery(b *testing.B) {
+	index := NewLSHIndex(LSHParams(SimilarityThreshold, numHashes))
+	for i := 0; i < 10000; i++ {
+		index.Insert(Int64ID(int64(i)), GenerateMinHash("document number "+strconv.Itoa(i)+" about nothing in particular"))
+	}
+	sig := GenerateMinHash("document number 42 about nothing in particular")
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		index.Query(sig)
+	}
+}
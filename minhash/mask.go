@@ -0,0 +1,87 @@
+package minhash
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Entity is a kind of token that varies between otherwise
+// identical documents (salaries, dates, tracking links, ...)
+type Entity int
+
+const (
+	NumberEntity Entity = 1 << iota
+	DateEntity
+	URLEntity
+	EmailEntity
+	UUIDEntity
+	HexEntity
+
+	AllEntities = NumberEntity | DateEntity | URLEntity | EmailEntity | UUIDEntity | HexEntity
+)
+
+const month = `(?:jan(?:uary)?|feb(?:ruary)?|mar(?:ch)?|apr(?:il)?|may|june?|july?|aug(?:ust)?|sept?(?:ember)?|oct(?:ober)?|nov(?:ember)?|dec(?:ember)?)`
+
+// entity patterns in the order they are masked.
+// Longer entities go first so a URL isn't
+// chopped up by the number pattern.
+var entityPatterns = []struct {
+	entity      Entity
+	placeholder string
+	pattern     *regexp.Regexp
+}{
+	{URLEntity, "<URL>", regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']*[^\s<>"'.,;:!?)]`)},
+	{EmailEntity, "<EMAIL>", regexp.MustCompile(`(?i)\b[a-z0-9._%+-]+@[a-z0-9.-]+\.[a-z]{2,}\b`)},
+	{UUIDEntity, "<UUID>", regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)},
+	{DateEntity, "<DATE>", regexp.MustCompile(`(?i)\b(?:` +
+		// 2024-01-31, 2024-01-31T09:30:00Z
+		`\d{4}-\d{2}-\d{2}(?:[t ]\d{2}:\d{2}(?::\d{2}(?:\.\d+)?)?(?:z|[+-]\d{2}:?\d{2})?)?` +
+		// 01/31/2024, 31.01.24
+		`|\d{1,2}[/.-]\d{1,2}[/.-]\d{2,4}` +
+		// January 31, 2024
+		`|` + month + `\.? \d{1,2}(?:st|nd|rd|th)?,? \d{4}` +
+		// 31 Jan 2024
+		`|\d{1,2} ` + month + `\.?,? \d{4}` +
+		`)\b`)},
+	{HexEntity, "<HEX>", regexp.MustCompile(`(?i)\b(?:0x[0-9a-f]+|[0-9a-f]{8,})\b`)},
+	{NumberEntity, "<NUM>", regexp.MustCompile(`\d+(?:[.,]\d+)*`)},
+}
+
+// EntityMasker is a Normalizer that replaces the configured
+// entities with placeholder tokens, so two postings that only
+// differ in salary, dates or tracking links shingle the same
+type EntityMasker struct {
+	Entities Entity
+}
+
+func NewEntityMasker(entities Entity) *EntityMasker {
+	return &EntityMasker{Entities: entities}
+}
+
+func (this *EntityMasker) Normalize(text string) string {
+	for _, p := range entityPatterns {
+		if this.Entities&p.entity == 0 {
+			continue
+		}
+
+		if p.entity == HexEntity {
+			text = p.pattern.ReplaceAllStringFunc(text, maskHex)
+		} else {
+			text = p.pattern.ReplaceAllString(text, p.placeholder)
+		}
+	}
+	return text
+}
+
+// maskHex only masks hex strings that mix digits and
+// letters, so words like "deadbeef" and plain numbers
+// are left alone
+func maskHex(s string) string {
+	if strings.HasPrefix(strings.ToLower(s), "0x") {
+		return "<HEX>"
+	}
+	if strings.ContainsAny(s, "0123456789") && strings.ContainsAny(strings.ToLower(s), "abcdef") {
+		return "<HEX>"
+	}
+	return s
+}
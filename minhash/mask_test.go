@@ -0,0 +1,37 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestEntityMasker(t *testing.T) {
+	m := NewEntityMasker(AllEntities)
+
+	assert.Equal(t, "Salary $<NUM> per year", m.Normalize("Salary $85,000.00 per year"))
+	assert.Equal(t, "Starts <DATE> or <DATE>", m.Normalize("Starts 2024-01-31 or 01/31/2024"))
+	assert.Equal(t, "Posted <DATE>.", m.Normalize("Posted January 31, 2024."))
+	assert.Equal(t, "Posted <DATE>", m.Normalize("Posted 31 Jan 2024"))
+	assert.Equal(t, "at <DATE> done", m.Normalize("at 2024-01-31T09:30:00Z done"))
+	assert.Equal(t, "Apply at <URL>.", m.Normalize("Apply at https://jobs.example.com/apply?id=123&utm_source=feed."))
+	assert.Equal(t, "Mail <EMAIL> now", m.Normalize("Mail jobs@example.com now"))
+	assert.Equal(t, "req <UUID>", m.Normalize("req 123e4567-e89b-12d3-a456-426614174000"))
+	assert.Equal(t, "commit <HEX> and <HEX>", m.Normalize("commit 9fceb02d0ae598e95dc970b74767f19372d61af8 and 0x1F"))
+	assert.Equal(t, "deadbeef facade", m.Normalize("deadbeef facade"))
+	assert.Equal(t, "call (<NUM>) <NUM>-<NUM>", m.Normalize("call (415) 555-1234"))
+
+	// only the configured entities are masked
+	m = NewEntityMasker(EmailEntity)
+	assert.Equal(t, "Mail <EMAIL> by 2024-01-31", m.Normalize("Mail jobs@example.com by 2024-01-31"))
+}
+
+func TestMaskedShingler(t *testing.T) {
+	left := "Warehouse associate needed in Oakland, pay is $18.50 an hour, apply by 2024-01-31 at https://jobs.example.com/?ref=a1"
+	right := "Warehouse associate needed in Oakland, pay is $21.00 an hour, apply by 2024-03-15 at https://jobs.example.com/?ref=zz9"
+
+	assert.False(t, Similar(left, right))
+
+	sh := NewNormalizedShingler(NewEntityMasker(AllEntities), DefaultShingler)
+	assert.Equal(t, 1.0, JaccardDistance(NewWordSetFromTextWith(left, sh), NewWordSetFromTextWith(right, sh)))
+	assert.Equal(t, GenerateMinHashWith(left, sh), GenerateMinHashWith(right, sh))
+}
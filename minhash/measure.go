@@ -0,0 +1,89 @@
+package minhash
+
+import (
+	"math"
+)
+
+// Measure scores how similar two WordSets are,
+// from 0 (nothing shared) to 1 (the same set)
+type Measure interface {
+	Similarity(left, right *WordSet) float64
+}
+
+// setMeasure is a Measure that only depends on the
+// size of both sets and of their intersection
+type setMeasure func(intersection, left, right float64) float64
+
+func (this setMeasure) Similarity(left, right *WordSet) float64 {
+	return this(float64(left.Intersection(right)), float64(left.Len()), float64(right.Len()))
+}
+
+func (this setMeasure) fromSizes(intersection, left, right float64) float64 {
+	return this(intersection, left, right)
+}
+
+// sizeMeasure is a Measure that can be computed from the size
+// of both sets and of their intersection alone, which lets set
+// types other than WordSet use it without being copied
+type sizeMeasure interface {
+	fromSizes(intersection, left, right float64) float64
+}
+
+var (
+	// Jaccard index, |A ∩ B| / |A ∪ B|
+	Jaccard Measure = setMeasure(func(i, l, r float64) float64 {
+		return ratio(i, l+r-i)
+	})
+
+	// Sørensen–Dice coefficient, 2|A ∩ B| / (|A| + |B|)
+	Dice Measure = setMeasure(func(i, l, r float64) float64 {
+		return ratio(2*i, l+r)
+	})
+
+	// Overlap coefficient, |A ∩ B| / min(|A|, |B|).
+	// A set is fully similar to any of its supersets.
+	Overlap Measure = setMeasure(func(i, l, r float64) float64 {
+		return ratio(i, math.Min(l, r))
+	})
+
+	// Cosine (Ochiai) coefficient, |A ∩ B| / sqrt(|A| |B|)
+	Cosine Measure = setMeasure(func(i, l, r float64) float64 {
+		return ratio(i, math.Sqrt(l*r))
+	})
+)
+
+// Tversky index, |A ∩ B| / (|A ∩ B| + Alpha |A - B| + Beta |B - A|).
+// Alpha = Beta = 1 is Jaccard and Alpha = Beta = 0.5 is Dice.
+// Alpha = 1, Beta = 0 measures how much of left is in right.
+type Tversky struct {
+	Alpha float64
+	Beta  float64
+}
+
+func (this Tversky) Similarity(left, right *WordSet) float64 {
+	return this.fromSizes(float64(left.Intersection(right)), float64(left.Len()), float64(right.Len()))
+}
+
+func (this Tversky) fromSizes(i, l, r float64) float64 {
+	return ratio(i, i+this.Alpha*(l-i)+this.Beta*(r-i))
+}
+
+// ratio is 0 when there is nothing to compare
+func ratio(num, denom float64) float64 {
+	if denom == 0 {
+		return 0
+	}
+	return num / denom
+}
+
+// Distance turns a similarity measure into a distance,
+// 1 - similarity. Distance(Jaccard, a, b) is the
+// Jaccard distance.
+func Distance(m Measure, left, right *WordSet) float64 {
+	return 1 - m.Similarity(left, right)
+}
+
+// SimilarWordSetsBy is SimilarWordSets for any Measure
+func SimilarWordSetsBy(left, right *WordSet, m Measure) bool {
+	return (&Comparator{Threshold: SimilarityThreshold, Inclusive: true, Measure: m}).SimilarWordSets(left, right)
+}
@@ -0,0 +1,42 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestMeasures(t *testing.T) {
+	a := newSet("a", "b", "c", "d")
+	b := newSet("c", "d", "e")
+
+	assert.Equal(t, 2.0/5.0, Jaccard.Similarity(a, b))
+	assert.Equal(t, JaccardDistance(a, b), Jaccard.Similarity(a, b))
+	assert.Equal(t, 4.0/7.0, Dice.Similarity(a, b))
+	assert.Equal(t, 2.0/3.0, Overlap.Similarity(a, b))
+	assert.Equal(t, 2.0/math.Sqrt(12), Cosine.Similarity(a, b))
+
+	assert.Equal(t, Jaccard.Similarity(a, b), Tversky{1, 1}.Similarity(a, b))
+	assert.Equal(t, Dice.Similarity(a, b), Tversky{0.5, 0.5}.Similarity(a, b))
+	assert.Equal(t, 2.0/4.0, Tversky{1, 0}.Similarity(a, b))
+	assert.Equal(t, 2.0/3.0, Tversky{0, 1}.Similarity(a, b))
+
+	assert.Equal(t, 3.0/5.0, Distance(Jaccard, a, b))
+	assert.Equal(t, 0.0, Distance(Jaccard, a, a))
+
+	for _, m := range []Measure{Jaccard, Dice, Overlap, Cosine, Tversky{0.3, 0.7}} {
+		assert.Equal(t, 1.0, m.Similarity(a, a.Clone()))
+		assert.Equal(t, 0.0, m.Similarity(a, newSet("x")))
+		assert.Equal(t, 0.0, m.Similarity(NewWordSet(), NewWordSet()))
+	}
+}
+
+func TestSimilarWordSetsBy(t *testing.T) {
+	a := newSet("a", "b", "c", "d", "e")
+	sub := newSet("a", "b", "c", "d")
+
+	assert.Equal(t, SimilarWordSets(a, sub), SimilarWordSetsBy(a, sub, Jaccard))
+	assert.True(t, SimilarWordSetsBy(a, newSet("a", "b"), Overlap))
+	assert.False(t, SimilarWordSetsBy(a, newSet("a", "b"), Jaccard))
+	assert.False(t, SimilarWordSetsBy(a, newSet("a", "b"), Dice))
+}
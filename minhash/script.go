@@ -0,0 +1,62 @@
+package minhash
+
+import (
+	"unicode"
+)
+
+// scripts that are written without spaces between words.
+// Splitting their text on spaces yields whole sentences
+// as tokens, so we fall back to character grams.
+var spacelessScripts = []*unicode.RangeTable{
+	unicode.Han,
+	unicode.Hiragana,
+	unicode.Katakana,
+	unicode.Thai,
+	unicode.Lao,
+	unicode.Khmer,
+	unicode.Myanmar,
+}
+
+func isSpaceless(r rune) bool {
+	return unicode.In(r, spacelessScripts...)
+}
+
+// combining marks (Thai vowels and tone marks, ...)
+// belong to the character before them
+func isMark(r rune) bool {
+	return unicode.In(r, unicode.Mn, unicode.Mc)
+}
+
+// segmentSpaceless splits a token so that every character
+// of a spaceless script becomes a token of its own. Runs of
+// other characters are kept together, so "iPhone手机" becomes
+// "iPhone", "手", "机". Tokens without spaceless characters
+// are returned as is.
+func segmentSpaceless(token string) []string {
+	parts := []string{}
+
+	start := 0
+	spaceless := false
+	for i, r := range token {
+		switch {
+		case isSpaceless(r) && !isMark(r):
+			if i > start {
+				parts = append(parts, token[start:i])
+			}
+			start = i
+			spaceless = true
+		case spaceless && isMark(r):
+			// stays with the current character
+		case spaceless:
+			parts = append(parts, token[start:i])
+			start = i
+			spaceless = false
+		}
+	}
+
+	if start < len(token) || len(parts) == 0 {
+		parts = append(parts, token[start:])
+	}
+
+	return parts
+}
@@ -0,0 +1,39 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestSegmentSpaceless(t *testing.T) {
+	assert.Equal(t, []string{"opportunity!"}, segmentSpaceless("opportunity!"))
+	assert.Equal(t, []string{""}, segmentSpaceless(""))
+	assert.Equal(t, []string{"招", "聘", "软", "件", "工", "程", "师"}, segmentSpaceless("招聘软件工程师"))
+	assert.Equal(t, []string{"iPhone", "手", "机", "2024"}, segmentSpaceless("iPhone手机2024"))
+	assert.Equal(t, []string{"エ", "ン", "ジ", "ニ", "ア"}, segmentSpaceless("エンジニア"))
+
+	// tone marks and vowel signs stay with their consonant
+	assert.Equal(t, []string{"ง", "า", "น"}, segmentSpaceless("งาน"))
+	assert.Equal(t, []string{"ที่", "ด"}, segmentSpaceless("ที่ด"))
+}
+
+func TestSpacelessShingles(t *testing.T) {
+	s := "招聘高级软件工程师，负责后端开发"
+
+	shingles := NewWordShingler(3).Shingles(s)
+	assert.Equal(t, "招 聘 高", shingles[0])
+	assert.Equal(t, len([]rune(s))-2, len(shingles))
+
+	// without segmentation the whole sentence is one token
+	assert.Equal(t, []string{}, (&WordShingler{K: 3}).Shingles(s))
+
+	edited := "招聘高级软件工程师，负责前端开发"
+	sim := JaccardSimilarity(s, edited)
+	assert.True(t, sim > 0.5 && sim < 1.0)
+
+	// streaming agrees with the in-memory path
+	mh, err := GenerateMinHashFromReader(strings.NewReader(s))
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHash(s), mh)
+}
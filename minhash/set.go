@@ -0,0 +1,151 @@
+package minhash
+
+import (
+	"fmt"
+	"iter"
+	"sort"
+	"strings"
+)
+
+// Set algebra on WordSets.
+//
+// Union, IntersectionSet, Difference and SymmetricDifference
+// return new sets and leave their operands alone. The ...With
+// variants update the receiver in place instead.
+
+// Clone returns a copy of the set
+func (this *WordSet) Clone() *WordSet {
+	clone := NewWordSet()
+	clone.id = this.id
+
+	for word := range this.membership {
+		clone.membership[word] = struct{}{}
+	}
+	return clone
+}
+
+// Union returns the words in either set
+func (this *WordSet) Union(other *WordSet) *WordSet {
+	union := this.Clone()
+	union.UnionWith(other)
+
+	return union
+}
+
+// IntersectionSet returns the words in both sets.
+// Intersection only counts them.
+func (this *WordSet) IntersectionSet(other *WordSet) *WordSet {
+	intersection := this.Clone()
+	intersection.IntersectWith(other)
+
+	return intersection
+}
+
+// Difference returns the words in this set but not in other
+func (this *WordSet) Difference(other *WordSet) *WordSet {
+	difference := this.Clone()
+	difference.DifferenceWith(other)
+
+	return difference
+}
+
+// SymmetricDifference returns the words in exactly one of the sets
+func (this *WordSet) SymmetricDifference(other *WordSet) *WordSet {
+	difference := this.Clone()
+	difference.SymmetricDifferenceWith(other)
+
+	return difference
+}
+
+// UnionWith adds the words of other to this set
+func (this *WordSet) UnionWith(other *WordSet) {
+	for word := range other.membership {
+		this.membership[word] = struct{}{}
+	}
+}
+
+// IntersectWith removes the words not in other from this set
+func (this *WordSet) IntersectWith(other *WordSet) {
+	for word := range this.membership {
+		if !other.Contains(word) {
+			delete(this.membership, word)
+		}
+	}
+}
+
+// DifferenceWith removes the words of other from this set
+func (this *WordSet) DifferenceWith(other *WordSet) {
+	for word := range other.membership {
+		delete(this.membership, word)
+	}
+}
+
+// SymmetricDifferenceWith removes the words of other that are
+// in this set and adds the ones that aren't
+func (this *WordSet) SymmetricDifferenceWith(other *WordSet) {
+	for word := range other.membership {
+		if this.Contains(word) {
+			delete(this.membership, word)
+		} else {
+			this.membership[word] = struct{}{}
+		}
+	}
+}
+
+// Equal reports whether both sets hold the same words
+func (this *WordSet) Equal(other *WordSet) bool {
+	return this.Len() == other.Len() && this.IsSubset(other)
+}
+
+// IsSubset reports whether every word of this set is in other
+func (this *WordSet) IsSubset(other *WordSet) bool {
+	for word := range this.membership {
+		if !other.Contains(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Members returns the words of the set in sorted order
+func (this *WordSet) Members() []string {
+	members := make([]string, 0, this.Len())
+
+	for word := range this.membership {
+		members = append(members, word)
+	}
+	sort.Strings(members)
+
+	return members
+}
+
+// All iterates over the words of the set in sorted order
+//
+//	for word := range ws.All() {
+//		...
+//	}
+func (this *WordSet) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, word := range this.Members() {
+			if !yield(word) {
+				return
+			}
+		}
+	}
+}
+
+// String lists the words of the set in sorted order,
+// so two sets can be diffed in tests and reports
+func (this *WordSet) String() string {
+	return formatMembers(this.Members())
+}
+
+// formatMembers quotes the sorted words of a set
+func formatMembers(members []string) string {
+	quoted := make([]string, 0, len(members))
+
+	for _, word := range members {
+		quoted = append(quoted, fmt.Sprintf("%q", word))
+	}
+	return "{" + strings.Join(quoted, ", ") + "}"
+}
@@ -0,0 +1,94 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func newSet(words ...string) *WordSet {
+	ws := NewWordSet()
+	for _, w := range words {
+		ws.Add(w)
+	}
+	return ws
+}
+
+func TestSetAlgebra(t *testing.T) {
+	a := newSet("a", "b", "c")
+	b := newSet("b", "c", "D")
+
+	assert.True(t, a.Union(b).Equal(newSet("a", "b", "c", "d")))
+	assert.True(t, a.IntersectionSet(b).Equal(newSet("b", "c")))
+	assert.True(t, a.Difference(b).Equal(newSet("a")))
+	assert.True(t, b.Difference(a).Equal(newSet("d")))
+	assert.True(t, a.SymmetricDifference(b).Equal(newSet("a", "d")))
+
+	assert.Equal(t, a.Intersection(b), a.IntersectionSet(b).Len())
+	assert.Equal(t, 4, a.Union(b).Len())
+
+	// operands are left alone
+	assert.True(t, a.Equal(newSet("a", "b", "c")))
+	assert.True(t, b.Equal(newSet("b", "c", "d")))
+}
+
+func TestSetAlgebraInPlace(t *testing.T) {
+	a := newSet("a", "b", "c")
+	a.UnionWith(newSet("c", "d"))
+	assert.True(t, a.Equal(newSet("a", "b", "c", "d")))
+
+	a.IntersectWith(newSet("b", "c", "d", "e"))
+	assert.True(t, a.Equal(newSet("b", "c", "d")))
+	assert.Equal(t, 3, a.Len())
+
+	a.DifferenceWith(newSet("b", "x"))
+	assert.True(t, a.Equal(newSet("c", "d")))
+	assert.False(t, a.Contains("b"))
+
+	a.SymmetricDifferenceWith(newSet("d", "e"))
+	assert.True(t, a.Equal(newSet("c", "e")))
+	assert.Equal(t, 2, a.Len())
+}
+
+func TestSetComparison(t *testing.T) {
+	a := newSet("a", "b")
+
+	assert.True(t, a.Equal(newSet("B", "A")))
+	assert.False(t, a.Equal(newSet("a")))
+	assert.False(t, a.Equal(newSet("a", "c")))
+
+	assert.True(t, newSet("a").IsSubset(a))
+	assert.True(t, a.IsSubset(a))
+	assert.True(t, NewWordSet().IsSubset(a))
+	assert.False(t, a.IsSubset(newSet("a")))
+
+	clone := a.Clone()
+	clone.Add("c")
+	assert.Equal(t, 2, a.Len())
+	assert.Equal(t, 3, clone.Len())
+	assert.True(t, a.IsSubset(clone))
+}
+
+func TestWordSetIteration(t *testing.T) {
+	ws := newSet("Gamma", "alpha", "beta")
+
+	assert.Equal(t, []string{"alpha", "beta", "gamma"}, ws.Members())
+	assert.Equal(t, []string{}, NewWordSet().Members())
+
+	words := []string{}
+	for word := range ws.All() {
+		words = append(words, word)
+	}
+	assert.Equal(t, ws.Members(), words)
+
+	// stopping early
+	words = []string{}
+	for word := range ws.All() {
+		words = append(words, word)
+		break
+	}
+	assert.Equal(t, []string{"alpha"}, words)
+
+	assert.Equal(t, `{"alpha", "beta", "gamma"}`, ws.String())
+	assert.Equal(t, `{}`, NewWordSet().String())
+	assert.Equal(t, `{"excellent job opportunity!", "job opportunity! need"}`, NewWordSetFromText("Excellent job opportunity! need").String())
+}
@@ -0,0 +1,138 @@
+package minhash
+
+import (
+	"strconv"
+	"strings"
+)
+
+// number of tokens in a shingle
+const shingleSize = 3
+
+// Shingler turns a document into shingles.
+// Both the exact (WordSet) and approximate (MinHash)
+// code paths shingle text through a Shingler so that
+// they always agree on what the members of a document are.
+//
+// Shingles returns the string form of every shingle and
+// Hashes returns the hashed form. Shingles are compared without
+// regard to case: Hashes(d)[i] must equal the hash of the lower
+// cased Shingles(d)[i], like a WordSet lower cases its members.
+// Use HashShingles to get this for free in a custom implementation.
+type Shingler interface {
+	Shingles(text string) []string
+	Hashes(text string) []uint32
+}
+
+// WordShingler builds shingles out of K consecutive
+// space separated tokens. Shingles are lower cased.
+//
+// With SegmentSpaceless, text in scripts written without
+// spaces (Chinese, Japanese, Thai, ...) is split into single
+// characters so the shingles become character K-grams.
+//
+// With Skip, skip-grams are emitted alongside the contiguous
+// shingles: every K tokens in order that skip over at most Skip
+// tokens in between. When words are swapped around or dropped,
+// the skip-grams jumping over them still match, so lightly edited
+// documents stay similar. An inserted word breaks as large a share
+// of skip-grams as of contiguous shingles though, compare the
+// contiguous shingles of one document with the skip-grams of the
+// other with SkipGramSimilarity to tolerate insertions as well.
+// The price is (K+Skip-1 choose K-1) times as many shingles per
+// document and a higher similarity between unrelated documents
+// sharing common words. See TestSkipGramTradeOff.
+type WordShingler struct {
+	K                int
+	Skip             int
+	SegmentSpaceless bool
+}
+
+// DefaultShingler is used by NewWordSetFromText and GenerateMinHash
+var DefaultShingler Shingler = NewWordShingler(shingleSize)
+
+// NewWordShingler panics if k is less than 1,
+// a shingle needs at least one token
+func NewWordShingler(k int) *WordShingler {
+	if k < 1 {
+		panic("minhash: WordShingler needs K >= 1, got " + strconv.Itoa(k))
+	}
+	return &WordShingler{K: k, SegmentSpaceless: true}
+}
+
+// Shingles scans the document per every K words
+// in the order they appear in the document.
+// Each one of these windows is a shingle
+func (this *WordShingler) Shingles(text string) []string {
+	shingles := []string{}
+
+	// reading from a string never fails
+	this.scan(strings.NewReader(text), func(s string, start, end int) {
+		shingles = append(shingles, s)
+	})
+
+	return shingles
+}
+
+func (this *WordShingler) split(token string) []string {
+	if this.SegmentSpaceless {
+		return segmentSpaceless(token)
+	}
+	return []string{token}
+}
+
+func (this *WordShingler) Hashes(text string) []uint32 {
+	return HashShingles(this.Shingles(text))
+}
+
+// HashShingles hashes the string form of shingles
+// into the 32 bit ids used by MinHash
+func HashShingles(shingles []string) []uint32 {
+	hashes := make([]uint32, 0, len(shingles))
+
+	for _, s := range shingles {
+		hashes = append(hashes, shingleHash(s))
+	}
+	return hashes
+}
+
+// shingleHash hashes the lower cased form of a shingle,
+// the same form a WordSet stores. Every shingle string
+// becomes a 32 bit id through here.
+func shingleHash(s string) uint32 {
+	return hash(strings.ToLower(s))
+}
+
+// joinWindows joins every window of k consecutive
+// tokens with a space
+func joinWindows(tokens []string, k int) []string {
+	shingles := []string{}
+
+	for i := 0; i < len(tokens)-k+1; i++ {
+		shingles = append(shingles, strings.Join(tokens[i:i+k], " "))
+	}
+	return shingles
+}
+
+// Normalizer rewrites a document before it is shingled
+type Normalizer interface {
+	Normalize(text string) string
+}
+
+// NormalizedShingler runs the text through a
+// Normalizer before handing it to the Shingler
+type NormalizedShingler struct {
+	Normalizer Normalizer
+	Shingler   Shingler
+}
+
+func NewNormalizedShingler(n Normalizer, sh Shingler) *NormalizedShingler {
+	return &NormalizedShingler{Normalizer: n, Shingler: sh}
+}
+
+func (this *NormalizedShingler) Shingles(text string) []string {
+	return this.Shingler.Shingles(this.Normalizer.Normalize(text))
+}
+
+func (this *NormalizedShingler) Hashes(text string) []uint32 {
+	return this.Shingler.Hashes(this.Normalizer.Normalize(text))
+}
@@ -0,0 +1,156 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+type upperShingler struct{}
+
+func (this upperShingler) Shingles(text string) []string {
+	return []string{text}
+}
+
+func (this upperShingler) Hashes(text string) []uint32 {
+	return HashShingles(this.Shingles(text))
+}
+
+func TestWordShingler(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js"
+	sh := NewWordShingler(3)
+
+	assert.Equal(t, []string{"excellent job opportunity!", "job opportunity! need", "opportunity! need node.js"}, sh.Shingles(s))
+	assert.Equal(t, []string{}, sh.Shingles("too short"))
+	assert.Equal(t, 4, len(NewWordShingler(2).Shingles(s)))
+}
+
+func TestShinglerConsistency(t *testing.T) {
+	// WordSet and MinHash must see exactly the same shingles
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume Excellent job opportunity!"
+
+	ws := NewWordSetFromText(s)
+	ss := doc2ShingleSet(s)
+	assert.Equal(t, ws.Len(), len(ss))
+
+	for w := range ws.membership {
+		_, ok := ss[string2Shingle(w)]
+		assert.True(t, ok, w)
+	}
+}
+
+func TestShinglerConsistencyIgnoresCase(t *testing.T) {
+	// shinglers that keep case still agree on the members
+	code := "func Foo(x int) { return foo(X) }"
+	logs := "Server: PROD\nserver: prod\nServer: prod\nRestarting"
+
+	for _, c := range []struct {
+		sh   Shingler
+		text string
+	}{
+		{&CodeShingler{K: 1, Language: GoCode}, code},
+		{&CodeShingler{K: 2, Language: GoCode}, code},
+		{NewLineShingler(1), logs},
+		{upperShingler{}, "One Whole Document"},
+	} {
+		ws := NewWordSetFromTextWith(c.text, c.sh)
+		ss := doc2ShingleSetWith(c.text, c.sh)
+		assert.Equal(t, ws.Len(), len(ss), c.text)
+
+		for w := range ws.membership {
+			_, ok := ss[string2Shingle(w)]
+			assert.True(t, ok, w)
+		}
+		assert.Equal(t, GenerateMinHashWith(c.text, c.sh), ws.MinHash())
+	}
+
+	// Foo and foo are the same member
+	assert.Equal(t, 9, NewWordSetFromTextWith(code, &CodeShingler{K: 1, Language: GoCode}).Len())
+
+	mh, err := GenerateMinHashFromReaderWith(strings.NewReader(logs), NewLineShingler(1))
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHashWith(logs, NewLineShingler(1)), mh)
+}
+
+func TestWordShinglerK(t *testing.T) {
+	assert.Panics(t, func() { NewWordShingler(0) })
+	assert.Panics(t, func() { NewWordShingler(-1) })
+
+	assert.Equal(t, []string{}, (&WordShingler{K: -1}).Shingles("a b c"))
+	assert.Equal(t, []string{}, (&WordShingler{K: 0, Skip: 2}).Shingles("a b c"))
+	assert.Equal(t, []string{"a b"}, (&WordShingler{K: 2, Skip: -1}).Shingles("a b"))
+}
+
+func TestCustomShingler(t *testing.T) {
+	s := "One Whole Document"
+	ws := NewWordSetFromTextWith(s, upperShingler{})
+
+	assert.Equal(t, 1, ws.Len())
+	assert.True(t, ws.Contains(s))
+	assert.Equal(t, GenerateMinHashWith(s, upperShingler{}), calculateMinHash(shingleSet{string2Shingle(s): true}))
+}
+
+func TestSkipGrams(t *testing.T) {
+	sh := NewWordShingler(3)
+	sh.Skip = 1
+
+	assert.Equal(t, []string{
+		"a b c", "a b d", "a c d",
+		"b c d", "b c e", "b d e",
+		"c d e",
+	}, sh.Shingles("a b c d e"))
+
+	spans := sh.Spans("a b c d e")
+	assert.Equal(t, "a b c d", "a b c d e"[spans[1].Start:spans[1].End])
+
+	sh.Skip = 2
+	assert.Equal(t, 4, len(sh.Shingles("a b c d")))
+
+	// streaming agrees with the in-memory path
+	mh, err := GenerateMinHashFromReaderWith(strings.NewReader("a b c d e f g"), sh)
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHashWith("a b c d e f g", sh), mh)
+}
+
+// Skip-grams make lightly edited documents more similar,
+// at the cost of making unrelated documents more similar too
+func TestSkipGramTradeOff(t *testing.T) {
+	s := "Excellent job opportunity for a developer with Node.js and MySQL experience, send us your resume today"
+	swapped := "Excellent opportunity job for a developer with Node.js and MySQL experience, send your us resume today"
+	dropped := "Excellent job opportunity for developer with Node.js and MySQL experience, send us resume today"
+	inserted := "Excellent job opportunity for a senior developer with Node.js and MySQL experience, send us your resume today"
+	unrelated := "Part time opportunity for a barista with coffee experience, send your resume to us"
+
+	contiguous := NewWordShingler(3)
+	skip := NewWordShingler(3)
+	skip.Skip = 1
+
+	jaccard := func(sh Shingler, left, right string) float64 {
+		return JaccardDistance(NewWordSetFromTextWith(left, sh), NewWordSetFromTextWith(right, sh))
+	}
+
+	// swapped words: 0.33 -> 0.57
+	assert.True(t, jaccard(skip, s, swapped) > jaccard(contiguous, s, swapped)+0.2)
+
+	// dropped words: 0.44 -> 0.48
+	assert.True(t, jaccard(skip, s, dropped) > jaccard(contiguous, s, dropped))
+
+	// an inserted word costs the skip-gram sets as much,
+	// matching contiguous shingles against skip-grams
+	// tolerates it: 0.71 -> 0.90
+	assert.InDelta(t, jaccard(contiguous, s, inserted), jaccard(skip, s, inserted), 0.02)
+	assert.True(t, SkipGramSimilarity(s, inserted, skip) > jaccard(contiguous, s, inserted)+0.15)
+
+	// and it works for the other edits too
+	assert.True(t, SkipGramSimilarity(s, swapped, skip) > jaccard(contiguous, s, swapped)+0.3)
+	assert.True(t, SkipGramSimilarity(s, dropped, skip) > jaccard(contiguous, s, dropped)+0.3)
+
+	// unrelated documents: 0.04 -> 0.06, 0.15 matched
+	// against skip-grams, still far from similar
+	assert.True(t, jaccard(skip, s, unrelated) > jaccard(contiguous, s, unrelated))
+	assert.True(t, jaccard(skip, s, unrelated) < 0.1)
+	assert.True(t, SkipGramSimilarity(s, unrelated, skip) < 0.2)
+
+	// three times as many shingles
+	assert.Equal(t, 3*len(contiguous.Shingles(s))-2, len(skip.Shingles(s)))
+}
@@ -0,0 +1,48 @@
+package minhash
+
+// SkipGramSets holds the contiguous shingles of a document
+// and its skip-grams (which include the contiguous shingles),
+// see SkipGramSimilarity.
+type SkipGramSets struct {
+	Contiguous *WordSet
+	Skip       *WordSet
+}
+
+// NewSkipGramSets shingles the text with sh, which should
+// have Skip set, and with sh without Skip
+func NewSkipGramSets(text string, sh *WordShingler) SkipGramSets {
+	contiguous := *sh
+	contiguous.Skip = 0
+
+	return SkipGramSets{
+		Contiguous: NewWordSetFromTextWith(text, &contiguous),
+		Skip:       NewWordSetFromTextWith(text, sh),
+	}
+}
+
+// Similarity is the share of the contiguous shingles of both
+// documents that the other document has as a skip-gram:
+//
+//	(|C1 ∩ S2| + |C2 ∩ S1|) / (|C1| + |C2|)
+//
+// A word inserted into a document only breaks the shingles
+// holding the word, the shingles it was inserted into are still
+// skip-grams of the edited document. The same goes for a word
+// dropped from a document, the other way around.
+func (this SkipGramSets) Similarity(other SkipGramSets) float64 {
+	shared := this.Contiguous.Intersection(other.Skip) + other.Contiguous.Intersection(this.Skip)
+
+	return ratio(float64(shared), float64(this.Contiguous.Len()+other.Contiguous.Len()))
+}
+
+// SkipGramSimilarity is an edit tolerant similarity of two
+// documents, see SkipGramSets.Similarity. Skip on sh is how many
+// inserted (or dropped) words in a row are tolerated, 1 will do
+// for an inserted adjective.
+//
+// Unlike the Jaccard index of skip-gram sets, which an inserted
+// word lowers about as much as it lowers the Jaccard index of
+// contiguous shingles, this similarity stays close to 1.
+func SkipGramSimilarity(left, right string, sh *WordShingler) float64 {
+	return NewSkipGramSets(left, sh).Similarity(NewSkipGramSets(right, sh))
+}
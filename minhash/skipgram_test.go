@@ -0,0 +1,34 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSkipGramSimilarity(t *testing.T) {
+	sh := NewWordShingler(3)
+	sh.Skip = 1
+
+	s := "a b c d e f"
+	assert.Equal(t, 1.0, SkipGramSimilarity(s, s, sh))
+
+	// "x" breaks the 3 shingles holding it, all 4
+	// shingles of s are skip-grams of the edited text
+	assert.Equal(t, 6.0/9.0, SkipGramSimilarity(s, "a b c x d e f", sh))
+	assert.Equal(t, 2.0/7.0, JaccardDistance(
+		NewWordSetFromTextWith(s, NewWordShingler(3)),
+		NewWordSetFromTextWith("a b c x d e f", NewWordShingler(3)),
+	))
+
+	// two words in a row need Skip = 2
+	assert.Equal(t, 4.0/10.0, SkipGramSimilarity(s, "a b c x y d e f", sh))
+	sh.Skip = 2
+	assert.Equal(t, 6.0/10.0, SkipGramSimilarity(s, "a b c x y d e f", sh))
+
+	sets := NewSkipGramSets(s, sh)
+	assert.Equal(t, 4, sets.Contiguous.Len())
+	assert.Equal(t, 2, sh.Skip)
+
+	assert.Equal(t, 0.0, SkipGramSimilarity("", "", sh))
+	assert.Equal(t, 0.0, SkipGramSimilarity(s, "u v w x y z", sh))
+}
@@ -0,0 +1,99 @@
+package minhash
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Helpers for sets kept as sorted slices without duplicates
+// (CompactWordSet, InternedWordSet and the similarity join),
+// whose set operations merge the two sorted slices.
+
+// sortedSet sorts the values and drops duplicates.
+// The result doesn't share memory with values, so the
+// capacity taken by duplicates isn't kept alive.
+func sortedSet[T cmp.Ordered](values []T) []T {
+	slices.Sort(values)
+	return slices.Clip(slices.Clone(slices.Compact(values)))
+}
+
+// mergeOverlap counts the values two sorted sets share
+func mergeOverlap[T cmp.Ordered](a, b []T) int {
+	overlap := 0
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			overlap += 1
+			i++
+			j++
+		}
+	}
+	return overlap
+}
+
+// mergeIntersection returns the values in both sorted sets
+func mergeIntersection[T cmp.Ordered](a, b []T) []T {
+	values := []T{}
+
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] < b[j]:
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			values = append(values, a[i])
+			i++
+			j++
+		}
+	}
+	return values
+}
+
+// mergeUnion returns the values in either sorted set
+func mergeUnion[T cmp.Ordered](a, b []T) []T {
+	values := make([]T, 0, len(a)+len(b))
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			values = append(values, a[i])
+			i++
+		case a[i] > b[j]:
+			values = append(values, b[j])
+			j++
+		default:
+			values = append(values, a[i])
+			i++
+			j++
+		}
+	}
+	values = append(values, a[i:]...)
+	return append(values, b[j:]...)
+}
+
+// mergeDifference returns the values of a that aren't in b
+func mergeDifference[T cmp.Ordered](a, b []T) []T {
+	values := []T{}
+
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] < b[j]:
+			values = append(values, a[i])
+			i++
+		case a[i] > b[j]:
+			j++
+		default:
+			i++
+			j++
+		}
+	}
+	return append(values, a[i:]...)
+}
@@ -0,0 +1,218 @@
+package minhash
+
+import (
+	"bufio"
+	"io"
+	"io/ioutil"
+	"strings"
+	"unicode/utf8"
+)
+
+// StreamShingler is a Shingler that can also hash
+// shingles straight off an io.Reader. Only a small
+// window of tokens is held in memory at a time, which
+// lets us sign documents that don't fit in memory
+// (log files, book dumps, ...)
+//
+// StreamHashes must call emit once for every value
+// Hashes would return for the same text.
+type StreamShingler interface {
+	Shingler
+	StreamHashes(r io.Reader, emit func(uint32)) error
+}
+
+// StreamHashes reads space separated tokens from r and
+// emits the hash of every window of K tokens.
+// Memory use is bounded by K + Skip tokens of at most
+// maxTokenSize bytes each, see scan.
+func (this *WordShingler) StreamHashes(r io.Reader, emit func(uint32)) error {
+	return this.scan(r, func(s string, start, end int) {
+		emit(shingleHash(s))
+	})
+}
+
+// longest token scan holds in memory. Text running on
+// for longer without a space (minified files, CJK text, ...)
+// is cut into tokens of at most this many bytes.
+const maxTokenSize = 64 * 1024
+
+// a token read by scan and its byte offsets in the text
+type scanToken struct {
+	text  string
+	start int
+	end   int
+}
+
+// scan slides a window of K + Skip tokens over the text read
+// from r and emits every shingle starting at the first token of
+// the window as a lower cased string, along with the byte
+// offsets of the shingle in the text.
+// Shingles, Spans and StreamHashes all go through here
+// so they can't disagree.
+func (this *WordShingler) scan(r io.Reader, emit func(s string, start, end int)) error {
+	if this.K < 1 {
+		return nil
+	}
+
+	br := bufio.NewReader(r)
+
+	size := this.K
+	if this.Skip > 0 {
+		size += this.Skip
+	}
+	window := make([]scanToken, 0, size)
+
+	slide := func() {
+		this.grams(window, emit)
+		copy(window, window[1:])
+		window = window[:len(window)-1]
+	}
+
+	pos := 0
+	push := func(token string) {
+		for _, t := range this.split(token) {
+			window = append(window, scanToken{text: t, start: pos, end: pos + len(t)})
+			pos += len(t)
+
+			if len(window) == size {
+				slide()
+			}
+		}
+	}
+
+	token := []byte{}
+	cut := false
+	for {
+		chunk, err := br.ReadSlice(' ')
+		if err != nil && err != io.EOF && err != bufio.ErrBufferFull {
+			return err
+		}
+		token = append(token, chunk...)
+
+		if err == bufio.ErrBufferFull {
+			if len(token) >= maxTokenSize {
+				n := this.cut(token)
+				push(string(token[:n]))
+				token = token[:copy(token, token[n:])]
+				cut = true
+			}
+			continue
+		}
+
+		eof := err == io.EOF
+		if !eof {
+			// drop the delimiter
+			token = token[:len(token)-1]
+		}
+
+		// the rest of a token that was cut may be empty
+		if len(token) > 0 || !cut {
+			push(string(token))
+		}
+		token = token[:0]
+		cut = false
+
+		if eof {
+			// shingles starting close to the end have
+			// fewer tokens to skip over
+			for len(window) >= this.K {
+				slide()
+			}
+			return nil
+		}
+		// skip the delimiter
+		pos += 1
+	}
+}
+
+// cut returns where to cut a token that has grown too long.
+// Cutting in front of a spaceless character doesn't change
+// how the token is segmented, so that is preferred. Otherwise
+// the token is cut after its last complete character.
+// Only the second half is searched so that every cut
+// takes off at least half of the token.
+func (this *WordShingler) cut(token []byte) int {
+	// the last character may not be read in full yet
+	end := len(token)
+	for n := 1; n < utf8.UTFMax && n <= len(token); n++ {
+		if utf8.RuneStart(token[len(token)-n]) {
+			if !utf8.FullRune(token[len(token)-n:]) {
+				end = len(token) - n
+			}
+			break
+		}
+	}
+
+	if this.SegmentSpaceless {
+		for i := end; i > len(token)/2; {
+			r, size := utf8.DecodeLastRune(token[:i])
+			i -= size
+
+			if isSpaceless(r) && !isMark(r) {
+				return i
+			}
+		}
+	}
+	return end
+}
+
+// grams emits every shingle of K tokens that starts with the
+// first token of the window. Without Skip that is the K
+// consecutive tokens, otherwise also every way of skipping
+// over tokens within the window, contiguous shingle first.
+func (this *WordShingler) grams(window []scanToken, emit func(s string, start, end int)) {
+	if this.K < 1 || len(window) < this.K {
+		return
+	}
+
+	gram := make([]string, this.K)
+	gram[0] = window[0].text
+
+	var pick func(j, from int)
+	pick = func(j, from int) {
+		for i := from; len(window)-i >= this.K-j; i++ {
+			gram[j] = window[i].text
+
+			if j == this.K-1 {
+				emit(strings.ToLower(strings.Join(gram, " ")), window[0].start, window[i].end)
+			} else {
+				pick(j+1, i+1)
+			}
+		}
+	}
+
+	if this.K == 1 {
+		emit(strings.ToLower(gram[0]), window[0].start, window[0].end)
+		return
+	}
+	pick(1, 1)
+}
+
+// GenerateMinHashFromReader generates a minhash from a
+// document read from r using the DefaultShingler.
+// The signature is the same as GenerateMinHash would
+// return for the same text.
+func GenerateMinHashFromReader(r io.Reader) (MinHash, error) {
+	if sh, ok := DefaultShingler.(StreamShingler); ok {
+		return GenerateMinHashFromReaderWith(r, sh)
+	}
+
+	// the default shingler can't stream so we read it all
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return GenerateMinHash(string(b)), nil
+}
+
+// GenerateMinHashFromReaderWith generates a minhash from a
+// document read from r, shingling it with the given StreamShingler
+func GenerateMinHashFromReaderWith(r io.Reader, sh StreamShingler) (MinHash, error) {
+	mh := newMinHasher()
+
+	if err := sh.StreamHashes(r, func(h uint32) { mh.add(shingle(h)) }); err != nil {
+		return nil, err
+	}
+
+	return mh.signature, nil
+}
@@ -0,0 +1,112 @@
+package minhash
+
+import (
+	"bytes"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestStreamHashes(t *testing.T) {
+	docs := []string{
+		"",
+		"short",
+		"Excellent job opportunity!",
+		"Excellent job opportunity! need Node.js, MYSQL and resume",
+		"double  spaced  text with a trailing space ",
+		"Line one\nline two\nline three of the document",
+	}
+
+	for _, d := range docs {
+		sh := NewWordShingler(3)
+
+		hashes := []uint32{}
+		err := sh.StreamHashes(iotest.OneByteReader(strings.NewReader(d)), func(h uint32) {
+			hashes = append(hashes, h)
+		})
+
+		assert.Nil(t, err)
+		assert.Equal(t, sh.Hashes(d), hashes, d)
+	}
+}
+
+func TestGenerateMinHashFromReader(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume"
+
+	mh, err := GenerateMinHashFromReader(strings.NewReader(s))
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHash(s), mh)
+
+	// larger than the bufio buffer
+	var buf bytes.Buffer
+	for i := 0; i < 10000; i++ {
+		buf.WriteString("the quick brown fox jumps over the lazy dog ")
+	}
+	big := buf.String()
+
+	mh, err = GenerateMinHashFromReader(&buf)
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHash(big), mh)
+
+	mh, err = GenerateMinHashFromReaderWith(strings.NewReader(s), NewWordShingler(2))
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHashWith(s, NewWordShingler(2)), mh)
+}
+
+func TestGenerateMinHashFromReaderError(t *testing.T) {
+	boom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader("some text "), iotest.ErrReader(boom))
+
+	_, err := GenerateMinHashFromReader(r)
+	assert.Equal(t, boom, err)
+}
+
+func TestStreamLongTokens(t *testing.T) {
+	// no spaces at all, cut into tokens of maxTokenSize bytes
+	long := strings.Repeat("abcdefgh", maxTokenSize/2)
+	sh := NewWordShingler(1)
+
+	shingles := sh.Shingles(long + " end")
+	assert.Equal(t, 5, len(shingles))
+	assert.Equal(t, strings.Repeat("abcdefgh", maxTokenSize/8), shingles[0])
+	assert.Equal(t, long, strings.Join(shingles[:4], ""))
+	assert.Equal(t, "end", shingles[4])
+
+	// cutting CJK text doesn't change how it is segmented
+	cjk := strings.Repeat("招聘软件工程师iPhone开发", maxTokenSize/4)
+	sh = NewWordShingler(2)
+	assert.Equal(t, joinWindows(segmentSpaceless(strings.ToLower(cjk)), 2), sh.Shingles(cjk))
+
+	mh, err := GenerateMinHashFromReaderWith(iotest.HalfReader(strings.NewReader(cjk)), sh)
+	assert.Nil(t, err)
+	assert.Equal(t, GenerateMinHashWith(cjk, sh), mh)
+
+	spans := sh.Spans(cjk)
+	last := spans[len(spans)-1]
+	assert.Equal(t, "开发", cjk[last.Start:last.End])
+}
+
+type repeatReader byte
+
+func (this repeatReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte(this)
+	}
+	return len(p), nil
+}
+
+func TestStreamWithoutSpaces(t *testing.T) {
+	// 8 MB without a space is read a token at a time
+	r := io.LimitReader(repeatReader('a'), 128*maxTokenSize)
+
+	n := 0
+	err := NewWordShingler(1).StreamHashes(r, func(h uint32) {
+		assert.Equal(t, hash(strings.Repeat("a", maxTokenSize)), h)
+		n += 1
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, 128, n)
+}
@@ -0,0 +1,331 @@
+package minhash
+
+import (
+	"iter"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// number of independently locked shards
+const syncShards = 32
+
+// SyncWordSet is a WordSet that is safe for concurrent use,
+// so ingestion goroutines can Add to the same set. Words are
+// spread over shards that each have their own lock, so writers
+// of different words rarely wait on each other.
+//
+// It has the methods of WordSet, including the set algebra.
+// SimilarityBy scores two SyncWordSets with any Measure, and
+// WordSet returns a plain copy.
+type SyncWordSet struct {
+	mu     sync.RWMutex
+	id     DocID
+	shards [syncShards]syncShard
+}
+
+type syncShard struct {
+	sync.RWMutex
+	membership map[string]struct{}
+}
+
+func NewSyncWordSet() *SyncWordSet {
+	var ws SyncWordSet
+	for i := range ws.shards {
+		ws.shards[i].membership = map[string]struct{}{}
+	}
+
+	return &ws
+}
+
+// shard returns the shard a lower cased word lives in.
+// FNV-1a is inlined to keep hashing off the heap.
+func (this *SyncWordSet) shard(word string) *syncShard {
+	h := uint32(2166136261)
+	for i := 0; i < len(word); i++ {
+		h ^= uint32(word[i])
+		h *= 16777619
+	}
+	return &this.shards[h%syncShards]
+}
+
+// ID of the document the set was built from
+func (this *SyncWordSet) ID() DocID {
+	this.mu.RLock()
+	defer this.mu.RUnlock()
+
+	return this.id
+}
+
+func (this *SyncWordSet) SetID(id DocID) {
+	this.mu.Lock()
+	defer this.mu.Unlock()
+
+	this.id = id
+}
+
+func (this *SyncWordSet) Add(word string) {
+	word = strings.ToLower(word)
+	s := this.shard(word)
+
+	s.Lock()
+	s.membership[word] = struct{}{}
+	s.Unlock()
+}
+
+func (this *SyncWordSet) Remove(word string) {
+	word = strings.ToLower(word)
+	s := this.shard(word)
+
+	s.Lock()
+	delete(s.membership, word)
+	s.Unlock()
+}
+
+func (this *SyncWordSet) Contains(word string) bool {
+	word = strings.ToLower(word)
+	s := this.shard(word)
+
+	s.RLock()
+	_, ok := s.membership[word]
+	s.RUnlock()
+
+	return ok
+}
+
+// Len counts the members. With concurrent writers
+// it's the size at some point during the call.
+func (this *SyncWordSet) Len() int {
+	n := 0
+
+	for i := range this.shards {
+		s := &this.shards[i]
+		s.RLock()
+		n += len(s.membership)
+		s.RUnlock()
+	}
+	return n
+}
+
+func (this *SyncWordSet) Intersection(other *SyncWordSet) int {
+	intersection := 0
+
+	// only one shard lock is held at a time, so two sets
+	// intersecting each other can't deadlock
+	for _, word := range this.words() {
+		if other.Contains(word) {
+			intersection += 1
+		}
+	}
+	return intersection
+}
+
+// WordSet returns a copy of the members as a plain WordSet
+func (this *SyncWordSet) WordSet() *WordSet {
+	ws := NewWordSet()
+	ws.id = this.ID()
+
+	for _, word := range this.words() {
+		ws.membership[word] = struct{}{}
+	}
+	return ws
+}
+
+func (this *SyncWordSet) words() []string {
+	words := []string{}
+
+	for i := range this.shards {
+		s := &this.shards[i]
+		s.RLock()
+		for word := range s.membership {
+			words = append(words, word)
+		}
+		s.RUnlock()
+	}
+	return words
+}
+
+// Set algebra, as for WordSet. Other sets are read into a
+// snapshot first, so no two locks are ever held at once and
+// sets can be combined with each other from any goroutine.
+// With concurrent writers the results reflect the sets at
+// some point during the call.
+
+// Clone returns a copy of the set
+func (this *SyncWordSet) Clone() *SyncWordSet {
+	clone := NewSyncWordSet()
+	clone.id = this.ID()
+
+	for _, word := range this.words() {
+		clone.Add(word)
+	}
+	return clone
+}
+
+// Union returns the words in either set
+func (this *SyncWordSet) Union(other *SyncWordSet) *SyncWordSet {
+	union := this.Clone()
+	union.UnionWith(other)
+
+	return union
+}
+
+// IntersectionSet returns the words in both sets.
+// Intersection only counts them.
+func (this *SyncWordSet) IntersectionSet(other *SyncWordSet) *SyncWordSet {
+	intersection := this.Clone()
+	intersection.IntersectWith(other)
+
+	return intersection
+}
+
+// Difference returns the words in this set but not in other
+func (this *SyncWordSet) Difference(other *SyncWordSet) *SyncWordSet {
+	difference := this.Clone()
+	difference.DifferenceWith(other)
+
+	return difference
+}
+
+// SymmetricDifference returns the words in exactly one of the sets
+func (this *SyncWordSet) SymmetricDifference(other *SyncWordSet) *SyncWordSet {
+	difference := this.Clone()
+	difference.SymmetricDifferenceWith(other)
+
+	return difference
+}
+
+// UnionWith adds the words of other to this set
+func (this *SyncWordSet) UnionWith(other *SyncWordSet) {
+	for _, word := range other.words() {
+		this.Add(word)
+	}
+}
+
+// IntersectWith removes the words not in other from this set
+func (this *SyncWordSet) IntersectWith(other *SyncWordSet) {
+	keep := map[string]struct{}{}
+	for _, word := range other.words() {
+		keep[word] = struct{}{}
+	}
+
+	for i := range this.shards {
+		s := &this.shards[i]
+		s.Lock()
+		for word := range s.membership {
+			if _, ok := keep[word]; !ok {
+				delete(s.membership, word)
+			}
+		}
+		s.Unlock()
+	}
+}
+
+// DifferenceWith removes the words of other from this set
+func (this *SyncWordSet) DifferenceWith(other *SyncWordSet) {
+	for _, word := range other.words() {
+		this.Remove(word)
+	}
+}
+
+// SymmetricDifferenceWith removes the words of other that are
+// in this set and adds the ones that aren't
+func (this *SyncWordSet) SymmetricDifferenceWith(other *SyncWordSet) {
+	for _, word := range other.words() {
+		s := this.shard(word)
+
+		s.Lock()
+		if _, ok := s.membership[word]; ok {
+			delete(s.membership, word)
+		} else {
+			s.membership[word] = struct{}{}
+		}
+		s.Unlock()
+	}
+}
+
+// Equal reports whether both sets hold the same words
+func (this *SyncWordSet) Equal(other *SyncWordSet) bool {
+	words := this.words()
+	return len(words) == other.Len() && other.containsAll(words)
+}
+
+// IsSubset reports whether every word of this set is in other
+func (this *SyncWordSet) IsSubset(other *SyncWordSet) bool {
+	return other.containsAll(this.words())
+}
+
+func (this *SyncWordSet) containsAll(words []string) bool {
+	for _, word := range words {
+		if !this.Contains(word) {
+			return false
+		}
+	}
+	return true
+}
+
+// Members returns the words of the set in sorted order
+func (this *SyncWordSet) Members() []string {
+	members := this.words()
+	sort.Strings(members)
+
+	return members
+}
+
+// All iterates over a snapshot of the words
+// of the set in sorted order
+func (this *SyncWordSet) All() iter.Seq[string] {
+	return func(yield func(string) bool) {
+		for _, word := range this.Members() {
+			if !yield(word) {
+				return
+			}
+		}
+	}
+}
+
+// String lists the words of the set in sorted order
+func (this *SyncWordSet) String() string {
+	return formatMembers(this.Members())
+}
+
+// MinHash signs the members of the set, see WordSet.MinHash
+func (this *SyncWordSet) MinHash() MinHash {
+	mh := newMinHasher()
+
+	for _, word := range this.words() {
+		mh.add(string2Shingle(word))
+	}
+	return mh.signature
+}
+
+// Signature signs the members of the set
+// and labels the signature with the set's ID
+func (this *SyncWordSet) Signature() Signature {
+	return Signature{ID: this.ID(), MinHash: this.MinHash()}
+}
+
+// SimilarityBy scores how similar the sets are with m.
+// The measures of this package only need the sizes of the
+// sets and of their intersection, so nothing is copied
+// for them. Other Measures are given WordSet copies.
+func (this *SyncWordSet) SimilarityBy(other *SyncWordSet, m Measure) float64 {
+	sm, ok := m.(sizeMeasure)
+	if !ok {
+		return m.Similarity(this.WordSet(), other.WordSet())
+	}
+
+	words := this.words()
+	intersection := 0
+	for _, word := range words {
+		if other.Contains(word) {
+			intersection += 1
+		}
+	}
+	return sm.fromSizes(float64(intersection), float64(len(words)), float64(other.Len()))
+}
+
+// SyncJaccard is JaccardDistance for SyncWordSets
+func SyncJaccard(left, right *SyncWordSet) float64 {
+	return left.SimilarityBy(right, Jaccard)
+}
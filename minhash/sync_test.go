@@ -0,0 +1,251 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// These tests are meant to be run with go test -race
+
+func TestSyncWordSet(t *testing.T) {
+	ws := NewSyncWordSet()
+	ws.Add("Node.js")
+	ws.Add("MYSQL")
+	ws.Add("mysql")
+
+	assert.Equal(t, 2, ws.Len())
+	assert.True(t, ws.Contains("MySQL"))
+
+	ws.Remove("mysql")
+	assert.False(t, ws.Contains("MYSQL"))
+	assert.Equal(t, 1, ws.Len())
+
+	ws.SetID("job-1")
+	plain := ws.WordSet()
+	assert.True(t, plain.Equal(newSet("node.js")))
+	assert.Equal(t, DocID("job-1"), plain.ID())
+}
+
+func newSyncSet(words ...string) *SyncWordSet {
+	ws := NewSyncWordSet()
+	for _, w := range words {
+		ws.Add(w)
+	}
+	return ws
+}
+
+func TestSyncWordSetAlgebra(t *testing.T) {
+	a := newSyncSet("a", "b", "c", "d")
+	b := newSyncSet("c", "d", "e")
+	a.SetID("a")
+
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, a.Union(b).Members())
+	assert.Equal(t, []string{"c", "d"}, a.IntersectionSet(b).Members())
+	assert.Equal(t, []string{"a", "b"}, a.Difference(b).Members())
+	assert.Equal(t, []string{"a", "b", "e"}, a.SymmetricDifference(b).Members())
+	assert.Equal(t, `{"a", "b", "c", "d"}`, a.String())
+	assert.Equal(t, DocID("a"), a.Clone().ID())
+
+	// the operands are left alone
+	assert.Equal(t, 4, a.Len())
+	assert.Equal(t, 3, b.Len())
+
+	assert.True(t, a.Equal(a.Clone()))
+	assert.False(t, a.Equal(b))
+	assert.True(t, newSyncSet("c", "D").IsSubset(b))
+	assert.False(t, a.IsSubset(b))
+
+	words := []string{}
+	for w := range b.All() {
+		words = append(words, w)
+	}
+	assert.Equal(t, []string{"c", "d", "e"}, words)
+
+	c := a.Clone()
+	c.IntersectWith(b)
+	assert.Equal(t, []string{"c", "d"}, c.Members())
+	c.UnionWith(newSyncSet("x"))
+	c.DifferenceWith(newSyncSet("c"))
+	assert.Equal(t, []string{"d", "x"}, c.Members())
+	c.SymmetricDifferenceWith(newSyncSet("d", "y"))
+	assert.Equal(t, []string{"x", "y"}, c.Members())
+	c.SymmetricDifferenceWith(c)
+	assert.Equal(t, 0, c.Len())
+
+	// the same answers as WordSet
+	assert.True(t, a.WordSet().SymmetricDifference(b.WordSet()).Equal(a.SymmetricDifference(b).WordSet()))
+}
+
+// a Measure that isn't computed from sizes alone
+type leftShare struct{}
+
+func (this leftShare) Similarity(left, right *WordSet) float64 {
+	return ratio(float64(left.Intersection(right)), float64(left.Len()))
+}
+
+func TestSyncWordSetMeasures(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume"
+	other := "Excellent job opportunity! need ASP.NET, MySQL and resume"
+
+	a := NewSyncWordSet()
+	for _, sh := range DefaultShingler.Shingles(s) {
+		a.Add(sh)
+	}
+	b := NewSyncWordSet()
+	for _, sh := range DefaultShingler.Shingles(other) {
+		b.Add(sh)
+	}
+
+	assert.Equal(t, JaccardSimilarity(s, other), SyncJaccard(a, b))
+	for _, m := range []Measure{Jaccard, Dice, Overlap, Cosine, Tversky{Alpha: 1}, leftShare{}} {
+		assert.Equal(t, m.Similarity(a.WordSet(), b.WordSet()), a.SimilarityBy(b, m))
+	}
+
+	assert.Equal(t, GenerateMinHash(s), a.MinHash())
+	a.SetID("job-1")
+	assert.Equal(t, Signature{ID: "job-1", MinHash: GenerateMinHash(s)}, a.Signature())
+}
+
+func TestSyncWordSetConcurrentAlgebra(t *testing.T) {
+	a := newSyncSet("a", "b", "c")
+	b := newSyncSet("b", "c", "d")
+
+	// combining two sets with each other from both
+	// sides at once must not deadlock
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				a.IntersectWith(b)
+				a.SymmetricDifferenceWith(b)
+				a.UnionWith(b)
+				a.Equal(b)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				b.IntersectWith(a)
+				b.DifferenceWith(newSyncSet("z"))
+				b.UnionWith(a)
+				SyncJaccard(b, a)
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSyncWordSetConcurrentAdd(t *testing.T) {
+	ws := NewSyncWordSet()
+	other := NewSyncWordSet()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for i := 0; i < 1000; i++ {
+				// every goroutine adds the same words
+				ws.Add("word " + strconv.Itoa(i))
+				other.Add("word " + strconv.Itoa(i*2))
+				ws.Contains("word " + strconv.Itoa(i))
+
+				if i%100 == 0 {
+					ws.Intersection(other)
+					other.Intersection(ws)
+					ws.Len()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1000, ws.Len())
+	assert.Equal(t, 500, ws.Intersection(other))
+	assert.Equal(t, 0.5*1000/1500, JaccardDistance(ws.WordSet(), other.WordSet()))
+}
+
+func TestSyncWordSetConcurrentRemove(t *testing.T) {
+	ws := NewSyncWordSet()
+	for i := 0; i < 1000; i++ {
+		ws.Add(strconv.Itoa(i))
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+
+			for i := g; i < 1000; i += 4 {
+				if i%2 == 0 {
+					ws.Remove(strconv.Itoa(i))
+				}
+				if i%100 == 0 {
+					ws.WordSet()
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 500, ws.Len())
+	assert.False(t, ws.Contains("2"))
+	assert.True(t, ws.Contains("3"))
+}
+
+var benchWords = func() []string {
+	words := []string{}
+	for i := 0; i < 10000; i++ {
+		words = append(words, "shingle number "+strconv.Itoa(i))
+	}
+	return words
+}()
+
+func BenchmarkWordSetAdd(b *testing.B) {
+	ws := NewWordSet()
+
+	for i := 0; i < b.N; i++ {
+		ws.Add(benchWords[i%len(benchWords)])
+	}
+}
+
+func BenchmarkSyncWordSetAdd(b *testing.B) {
+	ws := NewSyncWordSet()
+
+	for i := 0; i < b.N; i++ {
+		ws.Add(benchWords[i%len(benchWords)])
+	}
+}
+
+// a WordSet behind a single lock, what callers did before
+func BenchmarkMutexWordSetAddParallel(b *testing.B) {
+	ws := NewWordSet()
+	var mu sync.Mutex
+
+	var start int64
+	b.RunParallel(func(pb *testing.PB) {
+		for i := int(atomic.AddInt64(&start, 997)); pb.Next(); i++ {
+			mu.Lock()
+			ws.Add(benchWords[i%len(benchWords)])
+			mu.Unlock()
+		}
+	})
+}
+
+func BenchmarkSyncWordSetAddParallel(b *testing.B) {
+	ws := NewSyncWordSet()
+
+	var start int64
+	b.RunParallel(func(pb *testing.PB) {
+		for i := int(atomic.AddInt64(&start, 997)); pb.Next(); i++ {
+			ws.Add(benchWords[i%len(benchWords)])
+		}
+	})
+}
@@ -12,70 +12,118 @@ const (
 	SimilarityThreshold = 0.799
 )
 
+// WordSet is a set of lower cased words (or shingles).
+// Every method lower cases the words it is given, so
+// membership doesn't depend on case.
 type WordSet struct {
-	jid        int64
-	membership map[string]bool
-	length     int
+	id         DocID
+	membership map[string]struct{}
 }
 
 func NewWordSet() *WordSet {
 	var wordSet WordSet
-	wordSet.membership = map[string]bool{}
-	wordSet.length = 0
+	wordSet.membership = map[string]struct{}{}
 
 	return &wordSet
 }
 
 func NewWordSetFromText(text string) *WordSet {
-	ws := NewWordSet()
+	return NewWordSetFromTextWith(text, DefaultShingler)
+}
 
-	tokens := strings.Split(text, " ")
-	for i := 0; i < len(tokens)-2; i++ {
-		w := strings.ToLower(tokens[i] + " " + tokens[i+1] + " " + tokens[i+2])
+// NewWordSetFromTextWith builds a WordSet holding
+// the shingles produced by the given Shingler
+func NewWordSetFromTextWith(text string, sh Shingler) *WordSet {
+	ws := NewWordSet()
 
-		ws.membership[w] = true
+	for _, w := range sh.Shingles(text) {
+		ws.Add(w)
 	}
 
-	ws.length = len(ws.membership)
+	return ws
+}
+
+// NewWordSetFromDoc builds the WordSet of a
+// document and remembers which document it is
+func NewWordSetFromDoc(id DocID, text string) *WordSet {
+	return NewWordSetFromDocWith(id, text, DefaultShingler)
+}
+
+func NewWordSetFromDocWith(id DocID, text string, sh Shingler) *WordSet {
+	ws := NewWordSetFromTextWith(text, sh)
+	ws.id = id
 
 	return ws
 }
 
-func (this *WordSet) Add(word string) {
-	lower_word := strings.ToLower(word)
-	if _, ok := this.membership[lower_word]; !ok {
-		this.length += 1
-	}
-	this.membership[lower_word] = true
+// ID of the document the set was built from
+func (this *WordSet) ID() DocID {
+	return this.id
 }
 
-func (this *WordSet) Remove(word string) {
-	lower_word := strings.ToLower(word)
+func (this *WordSet) SetID(id DocID) {
+	this.id = id
+}
+
+// NewWordSetAndMinHash shingles the text once and
+// returns both its WordSet and its MinHash signature
+func NewWordSetAndMinHash(text string) (*WordSet, MinHash) {
+	return NewWordSetAndMinHashWith(text, DefaultShingler)
+}
+
+func NewWordSetAndMinHashWith(text string, sh Shingler) (*WordSet, MinHash) {
+	ws := NewWordSetFromTextWith(text, sh)
+
+	return ws, ws.MinHash()
+}
+
+// MinHash signs the members of the set, so the signature
+// estimates exactly the Jaccard index JaccardDistance computes.
+// For a set built with NewWordSetFromTextWith it is the same
+// signature GenerateMinHashWith returns for the text.
+func (this *WordSet) MinHash() MinHash {
+	mh := newMinHasher()
 
-	if _, ok := this.membership[lower_word]; ok {
-		this.length -= 1
+	for word := range this.membership {
+		mh.add(string2Shingle(word))
 	}
-	this.membership[word] = false
+	return mh.signature
+}
+
+// Signature signs the members of the set
+// and labels the signature with the set's ID
+func (this *WordSet) Signature() Signature {
+	return Signature{ID: this.id, MinHash: this.MinHash()}
+}
+
+func (this *WordSet) Add(word string) {
+	this.membership[strings.ToLower(word)] = struct{}{}
+}
+
+func (this *WordSet) Remove(word string) {
+	delete(this.membership, strings.ToLower(word))
 }
 
 func (this *WordSet) Len() int {
-	return this.length
+	return len(this.membership)
 }
 
 func (this *WordSet) Contains(word string) bool {
-
-	if _, ok := this.membership[strings.ToLower(word)]; ok {
-		return true
-	}
-	return false
+	_, ok := this.membership[strings.ToLower(word)]
+	return ok
 }
 
 func (this *WordSet) Intersection(other *WordSet) int {
 	// Compute set intersection
-	intersection := 0
+	// going over the smaller set
+	small, large := this, other
+	if small.Len() > large.Len() {
+		small, large = large, small
+	}
 
-	for key := range this.membership {
-		if other.Contains(key) {
+	intersection := 0
+	for key := range small.membership {
+		if _, ok := large.membership[key]; ok {
 			intersection += 1
 		}
 	}
@@ -85,12 +133,16 @@ func (this *WordSet) Intersection(other *WordSet) int {
 // Similar is based on Jaccard index
 // If their similarity thresholds are too high then we return true
 func Similar(left, right string) bool {
-	return JaccardSimilarity(left, right) >= SimilarityThreshold
+	return exactComparator.Similar(left, right)
 }
 
 // JaccardDistance calculate the similarity between two wordSets
 // Useful for determining if two block of texts are similar
 //
+// Despite its name this is the Jaccard index (a similarity),
+// it's kept for existing callers. See Jaccard and Distance
+// in measure.go for the index and the distance, 1 - J.
+//
 // Explanation: https://en.wikipedia.org/wiki/Jaccard_index
 func JaccardDistance(left, right *WordSet) float64 {
 	intersection := float64(left.Intersection(right))
@@ -101,7 +153,7 @@ func JaccardDistance(left, right *WordSet) float64 {
 
 // Same as Similarity but for word sets
 func SimilarWordSets(left, right *WordSet) bool {
-	return JaccardDistance(left, right) >= SimilarityThreshold
+	return exactComparator.SimilarWordSets(left, right)
 }
 
 // Calculates similarity between two arbitrary strings
@@ -2,7 +2,9 @@ package minhash
 
 import (
 	"github.com/stretchr/testify/assert"
+	"strings"
 	"testing"
+	"testing/quick"
 )
 
 func TestJaccardDistance(t *testing.T) {
@@ -95,3 +97,142 @@ func TestSimilarity(t *testing.T) {
 
 	assert.Equal(t, 1.0, JaccardSimilarity(s, s))
 }
+
+func TestWordSetRemove(t *testing.T) {
+	ws := NewWordSet()
+	ws.Add("Node.js")
+	ws.Add("MYSQL")
+
+	ws.Remove("mysql")
+	assert.False(t, ws.Contains("MySQL"))
+	assert.Equal(t, 1, ws.Len())
+
+	// removing twice or removing a stranger changes nothing
+	ws.Remove("MySQL")
+	ws.Remove("resume")
+	assert.Equal(t, 1, ws.Len())
+
+	other := NewWordSet()
+	other.Add("mysql")
+	other.Add("node.js")
+	assert.Equal(t, 1, ws.Intersection(other))
+	assert.Equal(t, 0.5, JaccardDistance(ws, other))
+}
+
+// wordSetOp is one Add or Remove applied to a WordSet.
+// Words are drawn from a small vocabulary in mixed case
+// so that sequences keep hitting the same members.
+type wordSetOp struct {
+	Add  bool
+	Word uint8
+	Case uint8
+}
+
+func (this wordSetOp) word() string {
+	w := []string{"alpha", "Beta", "GAMMA", "delta", "ePsIlOn"}[int(this.Word)%5]
+
+	switch this.Case % 3 {
+	case 1:
+		return strings.ToUpper(w)
+	case 2:
+		return strings.ToLower(w)
+	}
+	return w
+}
+
+// apply runs the ops on a WordSet and on a plain
+// map of lower cased words used as the model
+func apply(ops []wordSetOp) (*WordSet, map[string]bool) {
+	ws := NewWordSet()
+	model := map[string]bool{}
+
+	for _, op := range ops {
+		if op.Add {
+			ws.Add(op.word())
+			model[strings.ToLower(op.word())] = true
+		} else {
+			ws.Remove(op.word())
+			delete(model, strings.ToLower(op.word()))
+		}
+	}
+	return ws, model
+}
+
+func TestWordSetProperties(t *testing.T) {
+	vocabulary := []string{"alpha", "BETA", "Gamma", "delta", "epsilon"}
+
+	// the set agrees with the model on Len and Contains in any case
+	matchesModel := func(ops []wordSetOp) bool {
+		ws, model := apply(ops)
+
+		if ws.Len() != len(model) {
+			return false
+		}
+		for _, w := range vocabulary {
+			if ws.Contains(w) != model[strings.ToLower(w)] || ws.Contains(strings.ToUpper(w)) != ws.Contains(w) {
+				return false
+			}
+		}
+		return true
+	}
+	assert.Nil(t, quick.Check(matchesModel, nil))
+
+	// a removed word is never contained
+	removeDeletes := func(ops []wordSetOp, op wordSetOp) bool {
+		ws, _ := apply(ops)
+		ws.Remove(op.word())
+
+		return !ws.Contains(op.word()) && !ws.Contains(strings.ToLower(op.word()))
+	}
+	assert.Nil(t, quick.Check(removeDeletes, nil))
+
+	// Intersection and JaccardDistance count true members only
+	intersectionMatchesModel := func(left, right []wordSetOp) bool {
+		l, lm := apply(left)
+		r, rm := apply(right)
+
+		intersection := 0
+		for w := range lm {
+			if rm[w] {
+				intersection += 1
+			}
+		}
+		if l.Intersection(r) != intersection || r.Intersection(l) != intersection {
+			return false
+		}
+
+		union := len(lm) + len(rm) - intersection
+		return union == 0 || JaccardDistance(l, r) == float64(intersection)/float64(union)
+	}
+	assert.Nil(t, quick.Check(intersectionMatchesModel, nil))
+}
+
+func TestWordSetMinHash(t *testing.T) {
+	s := "Excellent job opportunity! need Node.js, MYSQL and resume"
+
+	ws, mh := NewWordSetAndMinHash(s)
+	assert.True(t, ws.Equal(NewWordSetFromText(s)))
+	assert.Equal(t, GenerateMinHash(s), mh)
+	assert.Equal(t, mh, ws.MinHash())
+
+	// the signature follows the set, not the text
+	ws.Add("Something Else Entirely")
+	ss := doc2ShingleSet(s)
+	ss[string2Shingle("something else entirely")] = true
+	assert.Equal(t, calculateMinHash(ss), ws.MinHash())
+
+	ws.Remove("something else entirely")
+	assert.Equal(t, mh, ws.MinHash())
+
+	ws.SetID("job-1")
+	assert.Equal(t, Signature{ID: "job-1", MinHash: mh}, ws.Signature())
+
+	// shinglers that keep case get the same signature
+	// from both entry points
+	for _, sh := range []Shingler{upperShingler{}, NewLineShingler(1), &CodeShingler{K: 2, Language: GoCode}} {
+		text := "One Whole Document\nfunc Foo(x int) { return foo(X) }\nONE whole document"
+
+		_, mh = NewWordSetAndMinHashWith(text, sh)
+		assert.Equal(t, GenerateMinHashWith(text, sh), mh)
+	}
+}
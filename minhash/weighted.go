@@ -0,0 +1,74 @@
+package minhash
+
+import (
+	"math"
+)
+
+// Weighted similarity measures for WordSets.
+//
+// JaccardDistance counts every shingle the same, so sharing a
+// common phrase weighs as much as sharing a rare one. These measures
+// weigh every shingle by its inverse document frequency in a corpus,
+// see CorpusStats. The corpus model can be built incrementally by
+// calling Add for every document in a stream.
+//
+// Members are hashed the way Shingler.Hashes hashes shingles, so
+// the stats can be built with any Shingler, as long as it's the
+// one the WordSets were built with.
+
+// WeightedJaccard is the IDF weighted Jaccard index,
+// the IDF of the shared shingles over the IDF of all shingles
+func WeightedJaccard(left, right *WordSet, stats *CorpusStats) float64 {
+	intersection := 0.0
+	union := 0.0
+
+	for w := range left.membership {
+		idf := stats.IDF(shingleHash(w))
+		union += idf
+		if right.Contains(w) {
+			intersection += idf
+		}
+	}
+
+	for w := range right.membership {
+		if !left.Contains(w) {
+			union += stats.IDF(shingleHash(w))
+		}
+	}
+
+	if union == 0 {
+		return 0
+	}
+	return intersection / union
+}
+
+// TFIDFCosine is the cosine similarity of the TF-IDF vectors
+// of two WordSets. A WordSet holds every shingle once, so the
+// term frequency is 1 for members and the vectors are the
+// IDF of the members.
+func TFIDFCosine(left, right *WordSet, stats *CorpusStats) float64 {
+	dot := 0.0
+
+	for w := range left.membership {
+		if right.Contains(w) {
+			idf := stats.IDF(shingleHash(w))
+			dot += idf * idf
+		}
+	}
+
+	norm := tfidfNorm(left, stats) * tfidfNorm(right, stats)
+	if norm == 0 {
+		return 0
+	}
+	return dot / norm
+}
+
+func tfidfNorm(ws *WordSet, stats *CorpusStats) float64 {
+	sum := 0.0
+
+	for w := range ws.membership {
+		idf := stats.IDF(shingleHash(w))
+		sum += idf * idf
+	}
+	return math.Sqrt(sum)
+}
@@ -0,0 +1,73 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"testing"
+)
+
+func TestIDF(t *testing.T) {
+	stats := NewCorpusStats()
+	stats.Add("a b c d")
+	stats.Add("a b c e")
+	stats.Add("x y z")
+
+	assert.Equal(t, math.Log(4.0/3.0)+1, stats.IDF(hash("a b c")))
+	assert.Equal(t, math.Log(4.0/2.0)+1, stats.IDF(hash("b c d")))
+	assert.Equal(t, math.Log(4.0)+1, stats.IDF(hash("never seen")))
+}
+
+func TestWeightedSimilarity(t *testing.T) {
+	corpus := []string{
+		"apply now to join our team today",
+		"apply now to join our sales team",
+		"apply now to join our kitchen crew",
+		"forklift operator apply now to join",
+	}
+
+	stats := NewCorpusStats()
+	for _, d := range corpus {
+		stats.Add(d)
+	}
+
+	// they share a common phrase, and a rare one
+	left := NewWordSetFromText("forklift operator apply now to join our team")
+	common := NewWordSetFromText("cashier apply now to join our kitchen crew")
+	rare := NewWordSetFromText("forklift operator apply now for night shift")
+
+	assert.True(t, JaccardDistance(left, common) > JaccardDistance(left, rare))
+	assert.True(t, WeightedJaccard(left, common, stats) < WeightedJaccard(left, rare, stats))
+	assert.True(t, TFIDFCosine(left, common, stats) < TFIDFCosine(left, rare, stats))
+
+	assert.Equal(t, 1.0, WeightedJaccard(left, left, stats))
+	assert.InDelta(t, 1.0, TFIDFCosine(left, left, stats), 1e-12)
+
+	empty := NewWordSet()
+	assert.Equal(t, 0.0, WeightedJaccard(empty, empty, stats))
+	assert.Equal(t, 0.0, TFIDFCosine(left, empty, stats))
+
+	// with no corpus every shingle weighs the same
+	assert.Equal(t, JaccardDistance(left, common), WeightedJaccard(left, common, NewCorpusStats()))
+}
+
+func TestWeightedSimilarityWith(t *testing.T) {
+	// a shingler that keeps case, the stats and the
+	// sets have to agree on the lower cased shingles
+	sh := NewLineShingler(1)
+
+	stats := NewCorpusStats()
+	stats.AddWith("Server: PROD\nRestarting", sh)
+	stats.AddWith("Server: PROD\nStopping", sh)
+	stats.AddWith("Server: TEST\nStarting", sh)
+
+	left := NewWordSetFromTextWith("Server: PROD\nRestarting", sh)
+	right := NewWordSetFromTextWith("Server: PROD\nStopping", sh)
+
+	common := stats.IDF(sh.Hashes("Server: PROD")[0])
+	rare := stats.IDF(sh.Hashes("Restarting")[0])
+	assert.Equal(t, math.Log(4.0/3.0)+1, common)
+	assert.Equal(t, math.Log(4.0/2.0)+1, rare)
+
+	assert.InDelta(t, common/(common+2*rare), WeightedJaccard(left, right, stats), 1e-12)
+	assert.InDelta(t, common*common/(common*common+rare*rare), TFIDFCosine(left, right, stats), 1e-12)
+}
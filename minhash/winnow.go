@@ -0,0 +1,198 @@
+package minhash
+
+import (
+	"sort"
+	"strings"
+)
+
+/*
+ ------------------ Winnowing ----------------------
+ MinHash tells us whether two documents are similar as a whole.
+ Winnowing (Schleimer, Wilkerson and Aiken, the algorithm behind MOSS)
+ tells us where they are similar.
+
+ The document is shingled and hashed exactly like it is for MinHash.
+ A window of w consecutive shingle hashes is slid over the document
+ and the minimum hash of every window is kept as a fingerprint, along
+ with the byte offsets of its shingle. Any passage shared by two documents
+ that is at least w+K-1 tokens long is guaranteed to share a fingerprint.
+
+ http://theory.stanford.edu/~aiken/publications/papers/sigmod03.pdf
+*/
+
+// default number of shingles in a winnowing window
+const winnowSize = 4
+
+// Span is a shingle and the byte offsets [Start, End)
+// of the text it was built from
+type Span struct {
+	Shingle string
+	Start   int
+	End     int
+}
+
+// SpanShingler is a Shingler that knows where
+// each of its shingles came from
+type SpanShingler interface {
+	Shingler
+	Spans(text string) []Span
+}
+
+// Fingerprint is a shingle hash selected by winnowing.
+// Index is the position of the shingle in the document
+// and [Start, End) are the byte offsets of its text.
+type Fingerprint struct {
+	Hash  uint32
+	Index int
+	Start int
+	End   int
+}
+
+// Passage is a region copied between two documents.
+// The offsets are byte offsets, End is exclusive.
+type Passage struct {
+	LeftStart  int
+	LeftEnd    int
+	RightStart int
+	RightEnd   int
+}
+
+// Spans returns the same shingles as Shingles along with
+// where they start and end in the text
+func (this *WordShingler) Spans(text string) []Span {
+	spans := []Span{}
+
+	// reading from a string never fails
+	this.scan(strings.NewReader(text), func(s string, start, end int) {
+		spans = append(spans, Span{Shingle: s, Start: start, End: end})
+	})
+
+	return spans
+}
+
+// Winnow fingerprints a document using the DefaultShingler
+// and a window of w shingles
+func Winnow(text string, w int) []Fingerprint {
+	sh, ok := DefaultShingler.(SpanShingler)
+	if !ok {
+		sh = NewWordShingler(shingleSize)
+	}
+	return WinnowWith(text, w, sh)
+}
+
+// WinnowWith fingerprints a document with the given SpanShingler.
+// In every window of w consecutive shingles the smallest hash
+// is selected (the rightmost one on ties) and recorded once.
+// Documents shorter than a window get their smallest hash.
+func WinnowWith(text string, w int, sh SpanShingler) []Fingerprint {
+	spans := sh.Spans(text)
+	fingerprints := []Fingerprint{}
+
+	hashes := make([]uint32, len(spans))
+	for i, span := range spans {
+		hashes[i] = shingleHash(span.Shingle)
+	}
+
+	if w < 1 {
+		w = 1
+	}
+	if w > len(spans) {
+		w = len(spans)
+	}
+
+	selected := -1
+	for i := 0; i+w <= len(spans) && w > 0; i++ {
+		min := i
+		for j := i; j < i+w; j++ {
+			if hashes[j] <= hashes[min] {
+				min = j
+			}
+		}
+
+		if min != selected {
+			selected = min
+			span := spans[min]
+			fingerprints = append(fingerprints, Fingerprint{
+				Hash:  hashes[min],
+				Index: min,
+				Start: span.Start,
+				End:   span.End,
+			})
+		}
+	}
+
+	return fingerprints
+}
+
+// CopiedPassages returns the passages of left that also appear
+// in right, using the DefaultShingler and a window of winnowSize
+func CopiedPassages(left, right string) []Passage {
+	return MatchFingerprints(Winnow(left, winnowSize), Winnow(right, winnowSize), winnowSize)
+}
+
+// MatchFingerprints pairs up the fingerprints the two documents
+// share and merges runs of pairs into passages. Two pairs are part of
+// the same passage when both sides move forward by at most w shingles,
+// the most winnowing can skip between selected fingerprints.
+func MatchFingerprints(left, right []Fingerprint, w int) []Passage {
+	byHash := map[uint32][]Fingerprint{}
+	for _, fp := range right {
+		byHash[fp.Hash] = append(byHash[fp.Hash], fp)
+	}
+
+	type pair struct {
+		left, right Fingerprint
+	}
+
+	pairs := []pair{}
+	for _, l := range left {
+		for _, r := range byHash[l.Hash] {
+			pairs = append(pairs, pair{l, r})
+		}
+	}
+
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].left.Index != pairs[j].left.Index {
+			return pairs[i].left.Index < pairs[j].left.Index
+		}
+		return pairs[i].right.Index < pairs[j].right.Index
+	})
+
+	// passages still being extended, with the
+	// last pair that was added to each
+	passages := []Passage{}
+	last := []pair{}
+
+	for _, p := range pairs {
+		extended := false
+
+		for i := range passages {
+			dl := p.left.Index - last[i].left.Index
+			dr := p.right.Index - last[i].right.Index
+
+			if dl > 0 && dl <= w && dr > 0 && dr <= w {
+				if p.left.End > passages[i].LeftEnd {
+					passages[i].LeftEnd = p.left.End
+				}
+				if p.right.End > passages[i].RightEnd {
+					passages[i].RightEnd = p.right.End
+				}
+				last[i] = p
+				extended = true
+				break
+			}
+		}
+
+		if !extended {
+			passages = append(passages, Passage{
+				LeftStart:  p.left.Start,
+				LeftEnd:    p.left.End,
+				RightStart: p.right.Start,
+				RightEnd:   p.right.End,
+			})
+			last = append(last, p)
+		}
+	}
+
+	return passages
+}
@@ -0,0 +1,62 @@
+package minhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSpans(t *testing.T) {
+	s := "Excellent job  opportunity! need"
+	spans := NewWordShingler(3).Spans(s)
+
+	assert.Equal(t, NewWordShingler(3).Shingles(s), []string{spans[0].Shingle, spans[1].Shingle, spans[2].Shingle})
+	assert.Equal(t, "Excellent job ", s[spans[0].Start:spans[0].End])
+	assert.Equal(t, " opportunity! need", s[spans[2].Start:spans[2].End])
+
+	spans = NewWordShingler(2).Spans("招聘 工程师")
+	assert.Equal(t, "聘 工", spans[1].Shingle)
+	assert.Equal(t, "聘 工", "招聘 工程师"[spans[1].Start:spans[1].End])
+}
+
+func TestWinnow(t *testing.T) {
+	s := "the quick brown fox jumps over the lazy dog and then runs far away from the farm"
+	spans := NewWordShingler(3).Spans(s)
+	fps := Winnow(s, 4)
+
+	assert.True(t, len(fps) > 0)
+	assert.True(t, len(fps) < len(spans))
+
+	// every window of 4 shingles holds a fingerprint
+	for i := 0; i+4 <= len(spans); i++ {
+		found := false
+		for _, fp := range fps {
+			found = found || (fp.Index >= i && fp.Index < i+4)
+		}
+		assert.True(t, found, i)
+	}
+
+	for _, fp := range fps {
+		assert.Equal(t, hash(spans[fp.Index].Shingle), fp.Hash)
+		assert.Equal(t, spans[fp.Index].Start, fp.Start)
+	}
+
+	// shorter than a window
+	assert.Equal(t, 1, len(Winnow("a short text", 4)))
+	assert.Equal(t, 0, len(Winnow("tiny", 4)))
+}
+
+func TestCopiedPassages(t *testing.T) {
+	copied := "We promote a fun fast paced environment that allows our trainers to help others and feel good while doing it."
+	left := "Fitness 19 Daly City is looking to expand its team of personal trainers. " + copied + " Apply today at our front desk."
+	right := "Join our friendly gym in Oakland as a front desk associate. " + copied + " Benefits include a free membership."
+
+	passages := CopiedPassages(left, right)
+	assert.Equal(t, 1, len(passages))
+
+	p := passages[0]
+	assert.Contains(t, copied, left[p.LeftStart:p.LeftEnd])
+	assert.Contains(t, copied, right[p.RightStart:p.RightEnd])
+	assert.True(t, p.LeftEnd-p.LeftStart > len(copied)/2)
+
+	assert.Equal(t, 0, len(CopiedPassages(left, "Something entirely different from the other posting")))
+}